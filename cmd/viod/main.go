@@ -52,6 +52,9 @@ func main() {
 	)
 	must.NotFail(ctxd.WrapError(ctx, err, "failed to init locator"))
 
+	err = app.AutoMigrate(ctx, deps)
+	must.NotFail(ctxd.WrapError(ctx, err, "failed to migrate the database"))
+
 	services := goservicing.WithGracefulShutDown(
 		func(ctx context.Context) {
 			app.GracefulDBShutdown(ctx, deps)
@@ -24,7 +24,8 @@ Feature: Expose geolocation information by IP
         "city": "DuBuquemouth",
         "latitude": -84.87503094689836,
         "longitude": 7.206435933364332,
-        "mystery_value": 7823011346
+        "mystery_value": "7823011346",
+        "dataset": "default"
     }
     """
 
@@ -36,4 +37,129 @@ Feature: Expose geolocation information by IP
   Scenario: Expose geolocation information by IP with invalid IP
     When I request HTTP endpoint with method "GET" and URI "/v1/geolocations/invalid-ip"
 
-    Then I should have response with status "Bad Request"
\ No newline at end of file
+    Then I should have response with status "Bad Request"
+
+  Scenario: Expose the selected attributes of the geolocation information by IP
+    Given these rows are stored in table "geolocation" of database "postgres":
+      | ip_address   | country_code | country  | city      | latitude | longitude | mystery_value | attributes                                        |
+      | 78.46.105.13 | DE           | Germany  | Nuremberg | 49.4478  | 11.0683   | 1234567890    | {"isp": "Hetzner Online", "connection_type": "dc"} |
+
+    When I request HTTP endpoint with method "GET" and URI "/v1/geolocations/78.46.105.13?attributes=isp"
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {
+        "ip_address": "78.46.105.13",
+        "country_code": "DE",
+        "country": "Germany",
+        "city": "Nuremberg",
+        "latitude": 49.4478,
+        "longitude": 11.0683,
+        "mystery_value": "1234567890",
+        "dataset": "default",
+        "attributes": {
+            "isp": "Hetzner Online"
+        }
+    }
+    """
+
+  Scenario: Expose geolocation information by IP as of a past time
+    Given these rows are stored in table "geolocation_history" of database "postgres":
+      | ip_address     | country_code | country  | city      | latitude | longitude | mystery_value | valid_from           | valid_to             |
+      | 200.106.141.15 | SI           | Slovenia | Ljubljana | 46.0569  | 14.5058   | 7823011346    | 2020-01-01T00:00:00Z | 2021-01-01T00:00:00Z |
+
+    When I request HTTP endpoint with method "GET" and URI "/v1/geolocations/200.106.141.15?as_of=2020-06-01T00:00:00Z"
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {
+        "ip_address": "200.106.141.15",
+        "country_code": "SI",
+        "country": "Slovenia",
+        "city": "Ljubljana",
+        "latitude": 46.0569,
+        "longitude": 14.5058,
+        "mystery_value": "7823011346",
+        "dataset": "default"
+    }
+    """
+
+  Scenario: Expose geolocation information by IP as of a time before it was known
+    When I request HTTP endpoint with method "GET" and URI "/v1/geolocations/200.106.141.15?as_of=2019-01-01T00:00:00Z"
+
+    Then I should have response with status "Not Found"
+
+  Scenario: Expose geolocation information by IP from the first dataset having it
+    Given these rows are stored in table "geolocation" of database "postgres":
+      | ip_address    | country_code | country  | city      | latitude | longitude | mystery_value | dataset     |
+      | 160.103.7.140 | CZ           | Czechia  | Prague    | 50.0755  | 14.4378   | 7301823115    | ip2location |
+      | 78.46.105.13  | DE           | Germany  | Nuremberg | 49.4478  | 11.0683   | 1234567890    | ip2location |
+
+    When I request HTTP endpoint with method "GET" and URI "/v1/geolocations/78.46.105.13?datasets=maxmind&datasets=ip2location"
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {
+        "ip_address": "78.46.105.13",
+        "country_code": "DE",
+        "country": "Germany",
+        "city": "Nuremberg",
+        "latitude": 49.4478,
+        "longitude": 11.0683,
+        "mystery_value": "1234567890",
+        "dataset": "ip2location"
+    }
+    """
+
+    When I request HTTP endpoint with method "GET" and URI "/v1/geolocations/160.103.7.140?datasets=ip2location&datasets=default"
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {
+        "ip_address": "160.103.7.140",
+        "country_code": "CZ",
+        "country": "Czechia",
+        "city": "Prague",
+        "latitude": 50.0755,
+        "longitude": 14.4378,
+        "mystery_value": "7301823115",
+        "dataset": "ip2location"
+    }
+    """
+
+  Scenario: Expose geolocation detail by IP to the admin
+    Given these rows are stored in table "geolocation" of database "postgres":
+      | ip_address   | country_code | country | city      | latitude | longitude | mystery_value | source_file   | source_line |
+      | 78.46.105.13 | DE           | Germany | Nuremberg | 49.4478  | 11.0683   | 1234567890    | data_dump.csv | 42          |
+
+    When I request HTTP endpoint with method "GET" and URI "/v1/admin/geolocations/78.46.105.13"
+    And I request HTTP endpoint with header "Authorization: Bearer admin-token"
+
+    Then I should have response with status "OK"
+    And I should have response with body
+    """
+    {
+        "geolocation": {
+            "ip_address": "78.46.105.13",
+            "country_code": "DE",
+            "country": "Germany",
+            "city": "Nuremberg",
+            "latitude": 49.4478,
+            "longitude": 11.0683,
+            "mystery_value": "1234567890",
+            "dataset": "default"
+        },
+        "source_file": "data_dump.csv",
+        "source_line": "42"
+    }
+    """
+
+  Scenario: Expose geolocation detail by IP without the admin token
+    When I request HTTP endpoint with method "GET" and URI "/v1/admin/geolocations/78.46.105.13"
+    And I request HTTP endpoint with header "Authorization: Bearer wrong-token"
+
+    Then I should have response with status "Unauthorized"
@@ -0,0 +1,243 @@
+package vio_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/dbdog"
+	"github.com/cucumber/godog"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/platform/app"
+	"github.com/dohernandez/vio/internal/platform/config"
+	stplatform "github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/dohernandez/vio/pkg/must"
+)
+
+// memoryDSN is the in-memory database the features run against, shared by the service and the commands.
+const memoryDSN = "memory://features"
+
+// TestIntegrationMemory runs the features against the in-memory database, without any external dependency.
+func TestIntegrationMemory(t *testing.T) {
+	ctx := context.Background()
+
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	// The commands run by the features load the configuration from the env.
+	t.Setenv("DATABASE_DSN", memoryDSN)
+
+	// load configurations
+	err := config.WithEnvFiles(".env.integration-test")
+	must.NotFail(ctxd.WrapError(ctx, err, "failed to load env from .env.integration-test"))
+	cfg, err := config.GetConfig()
+	must.NotFail(ctxd.WrapError(ctx, err, "failed to load configurations"))
+
+	runFeatures(t, cfg, func(deps *app.Locator) stepsRegistrar {
+		m := memoryManager{
+			db:     deps.Memory,
+			name:   "postgres",
+			mapper: newTableMapper(),
+		}
+
+		return m.RegisterSteps
+	})
+}
+
+var (
+	errUnknownMemoryDatabase = errors.New("unknown database")
+	errUnknownMemoryTable    = errors.New("unknown table")
+	errMemoryRowsMismatch    = errors.New("rows mismatch")
+)
+
+// memoryManager implements the database steps of the features with the in-memory database.
+type memoryManager struct {
+	db *stplatform.Memory
+	// name is the name the features give to the database.
+	name   string
+	mapper *dbdog.TableMapper
+}
+
+// RegisterSteps adds the database steps to the scenario.
+func (m *memoryManager) RegisterSteps(s *godog.ScenarioContext) {
+	s.Step(`there is a clean "([^"]*)" database$`, m.thereIsACleanDatabase)
+	s.Step(`these rows are stored in table "([^"]*)" of database "([^"]*)"[:]?$`, m.theseRowsAreStoredInTableOfDatabase)
+	s.Step(`only these rows are available in table "([^"]*)" of database "([^"]*)"[:]?$`,
+		func(table, dbName string, data *godog.Table) error {
+			return m.assertRows(table, dbName, dbdog.Rows(data), true)
+		})
+	s.Step(`these rows are available in table "([^"]*)" of database "([^"]*)"[:]?$`,
+		func(table, dbName string, data *godog.Table) error {
+			return m.assertRows(table, dbName, dbdog.Rows(data), false)
+		})
+}
+
+func (m *memoryManager) checkDatabase(dbName string) error {
+	if dbName != m.name {
+		return fmt.Errorf("%w %s", errUnknownMemoryDatabase, dbName)
+	}
+
+	return nil
+}
+
+func (m *memoryManager) thereIsACleanDatabase(dbName string) error {
+	if err := m.checkDatabase(dbName); err != nil {
+		return err
+	}
+
+	m.db.Reset()
+
+	return nil
+}
+
+func (m *memoryManager) theseRowsAreStoredInTableOfDatabase(table, dbName string, data *godog.Table) error {
+	if err := m.checkDatabase(dbName); err != nil {
+		return err
+	}
+
+	switch table {
+	case stplatform.GeolocationTable:
+		rows, err := m.mapper.SliceFromTable(dbdog.Rows(data), new(model.Geolocation))
+		if err != nil {
+			return err
+		}
+
+		// Rows are stored through the repository of their dataset, so that their history is recorded.
+		byDataset := make(map[string][]*model.Geolocation)
+
+		for _, geo := range rows.([]model.Geolocation) {
+			byDataset[geo.Dataset] = append(byDataset[geo.Dataset], &geo)
+		}
+
+		for dataset, geos := range byDataset {
+			repo := stplatform.NewMemoryGeolocation(m.db, stplatform.WithDataset(dataset))
+
+			if err := repo.SaveGeolocation(context.Background(), geos); err != nil {
+				return err
+			}
+		}
+	case stplatform.GeolocationHistoryTable:
+		rows, err := m.mapper.SliceFromTable(dbdog.Rows(data), new(stplatform.GeolocationHistory))
+		if err != nil {
+			return err
+		}
+
+		m.db.StoreHistory(rows.([]stplatform.GeolocationHistory)...)
+	default:
+		return fmt.Errorf("%w %s in database %s", errUnknownMemoryTable, table, dbName)
+	}
+
+	return nil
+}
+
+// assertRows checks the rows are available in the table, comparing the columns of the data. Exhaustive checks no
+// other row is available.
+func (m *memoryManager) assertRows(table, dbName string, data [][]string, exhaustive bool) error {
+	if err := m.checkDatabase(dbName); err != nil {
+		return err
+	}
+
+	var (
+		available []any
+		item      any
+	)
+
+	switch table {
+	case stplatform.GeolocationTable:
+		item = new(model.Geolocation)
+
+		for _, geo := range m.db.Geolocations() {
+			available = append(available, geo)
+		}
+	case stplatform.GeolocationHistoryTable:
+		item = new(stplatform.GeolocationHistory)
+
+		for _, h := range m.db.History() {
+			available = append(available, h)
+		}
+	default:
+		return fmt.Errorf("%w %s in database %s", errUnknownMemoryTable, table, dbName)
+	}
+
+	availableValues := make([]url.Values, 0, len(available))
+
+	for _, row := range available {
+		values, err := m.mapper.Encoder.Encode(row)
+		if err != nil {
+			return err
+		}
+
+		availableValues = append(availableValues, values)
+	}
+
+	colNames := data[0]
+	matched := make([]bool, len(availableValues))
+
+	err := m.mapper.IterateTable(dbdog.IterateConfig{
+		Data: data,
+		Item: item,
+		ReceiveRow: func(_ int, row any, _ []string, rawValues []string) error {
+			expected, err := m.mapper.Encoder.Encode(row)
+			if err != nil {
+				return err
+			}
+
+			for i, values := range availableValues {
+				if !matched[i] && sameColumns(colNames, expected, values) {
+					matched[i] = true
+
+					return nil
+				}
+			}
+
+			return fmt.Errorf("%w: row %s not available in table %s, available rows:\n%s",
+				errMemoryRowsMismatch, strings.Join(rawValues, " | "), table, renderRows(colNames, availableValues))
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	if exhaustive && len(data)-1 != len(availableValues) {
+		return fmt.Errorf("%w: %d rows expected in table %s, %d available:\n%s",
+			errMemoryRowsMismatch, len(data)-1, table, len(availableValues), renderRows(colNames, availableValues))
+	}
+
+	return nil
+}
+
+// sameColumns tells whether the values of the columns are the same.
+func sameColumns(colNames []string, expected, values url.Values) bool {
+	for _, col := range colNames {
+		if !slices.Equal(expected[col], values[col]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// renderRows renders the columns of the rows, for the failure messages.
+func renderRows(colNames []string, rows []url.Values) string {
+	var sb strings.Builder
+
+	sb.WriteString(strings.Join(colNames, " | "))
+
+	for _, values := range rows {
+		cells := make([]string, 0, len(colNames))
+
+		for _, col := range colNames {
+			cells = append(cells, values.Get(col))
+		}
+
+		sb.WriteString("\n" + strings.Join(cells, " | "))
+	}
+
+	return sb.String()
+}
@@ -40,6 +40,26 @@ func TestIntegration(t *testing.T) {
 	cfg, err := config.GetConfig()
 	must.NotFail(ctxd.WrapError(ctx, err, "failed to load configurations"))
 
+	runFeatures(t, cfg, func(deps *app.Locator) stepsRegistrar {
+		dbm := initDBManager(deps.Storage)
+		dbmCleaner := initDBMCleaner(dbm)
+
+		return func(s *godog.ScenarioContext) {
+			dbm.RegisterSteps(s)
+			dbmCleaner.RegisterSteps(s)
+		}
+	})
+}
+
+// stepsRegistrar registers the database steps of the scenarios.
+type stepsRegistrar func(s *godog.ScenarioContext)
+
+// runFeatures starts the services with the configuration and runs the features against them.
+func runFeatures(t *testing.T, cfg *config.Config, dbSteps func(deps *app.Locator) stepsRegistrar) {
+	t.Helper()
+
+	ctx := context.Background()
+
 	cfg.Environment = "test"
 	cfg.Log.Output = io.Discard
 
@@ -68,8 +88,10 @@ func TestIntegration(t *testing.T) {
 	)
 	must.NotFail(ctxd.WrapError(ctx, err, "failed to init service locator"))
 
-	dbm := initDBManager(deps.Storage)
-	dbmCleaner := initDBMCleaner(dbm)
+	err = app.AutoMigrate(ctx, deps)
+	must.NotFail(ctxd.WrapError(ctx, err, "failed to migrate the database"))
+
+	registerDBSteps := dbSteps(deps)
 
 	services := goservicing.WithGracefulShutDown(
 		func(ctx context.Context) {
@@ -99,8 +121,7 @@ func TestIntegration(t *testing.T) {
 	feature.RunFeatures(t, "features", func(_ *testing.T, s *godog.ScenarioContext) {
 		local.RegisterSteps(s)
 
-		dbm.RegisterSteps(s)
-		dbmCleaner.RegisterSteps(s)
+		registerDBSteps(s)
 
 		clock.RegisterContext(s)
 
@@ -110,9 +131,33 @@ func TestIntegration(t *testing.T) {
 	must.NotFail(services.Close())
 }
 
-func initDBManager(storage *sqluct.Storage) *dbdog.Manager {
+// newTableMapper creates the mapper of the rows of the tables, with the attributes as JSON.
+func newTableMapper() *dbdog.TableMapper {
 	tableMapper := dbdog.NewTableMapper()
 
+	tableMapper.Decoder.RegisterFunc(func(s string) (any, error) {
+		var attributes model.Attributes
+
+		err := attributes.Scan(s)
+
+		return attributes, err
+	}, model.Attributes{})
+
+	tableMapper.Encoder.RegisterFunc(func(v any) (string, error) {
+		value, err := v.(model.Attributes).Value()
+		if err != nil {
+			return "", err
+		}
+
+		return value.(string), nil
+	}, model.Attributes{})
+
+	return tableMapper
+}
+
+func initDBManager(storage *sqluct.Storage) *dbdog.Manager {
+	tableMapper := newTableMapper()
+
 	dbm := dbdog.Manager{
 		TableMapper: tableMapper,
 	}
@@ -121,7 +166,8 @@ func initDBManager(storage *sqluct.Storage) *dbdog.Manager {
 		"postgres": {
 			Storage: storage,
 			Tables: map[string]interface{}{
-				stplatform.GeolocationTable: new(model.Geolocation),
+				stplatform.GeolocationTable:        new(model.Geolocation),
+				stplatform.GeolocationHistoryTable: new(stplatform.GeolocationHistory),
 			},
 		},
 	}
@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/bool64/ctxd"
+)
+
+// Attributes are the extra attributes of the geolocation data, the values of the columns of the input data not
+// mapped to a field, indexed by column name.
+//
+// Attributes are stored as a JSON object.
+type Attributes map[string]string
+
+// Select returns the attributes with the given names, all of them when no name is given.
+func (a Attributes) Select(names ...string) Attributes {
+	if len(names) == 0 || a == nil {
+		return a
+	}
+
+	selected := make(Attributes, len(names))
+
+	for _, name := range names {
+		if v, ok := a[name]; ok {
+			selected[name] = v
+		}
+	}
+
+	return selected
+}
+
+// Names returns the names of the attributes, sorted.
+func (a Attributes) Names() []string {
+	names := make([]string, 0, len(a))
+
+	for name := range a {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// Value implements driver.Valuer.
+func (a Attributes) Value() (driver.Value, error) {
+	if len(a) == 0 {
+		return "{}", nil
+	}
+
+	b, err := json.Marshal(map[string]string(a))
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// Scan implements sql.Scanner.
+func (a *Attributes) Scan(src any) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+
+		return nil
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+	default:
+		return ctxd.NewError(context.Background(), "unsupported attributes type", "type", fmt.Sprintf("%T", v))
+	}
+
+	var m map[string]string
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return ctxd.WrapError(context.Background(), err, "invalid attributes")
+	}
+
+	if len(m) == 0 {
+		m = nil
+	}
+
+	*a = m
+
+	return nil
+}
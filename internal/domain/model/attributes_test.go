@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAttributes_Select(t *testing.T) {
+	t.Parallel()
+
+	attrs := Attributes{"connection_type": "cable", "isp": "Telekom Slovenije"}
+
+	require.Equal(t, attrs, attrs.Select())
+	require.Equal(t, Attributes{"isp": "Telekom Slovenije"}, attrs.Select("isp", "unknown"))
+	require.Empty(t, attrs.Select("unknown"))
+	require.Nil(t, Attributes(nil).Select("isp"))
+}
+
+func TestAttributes_Value(t *testing.T) {
+	t.Parallel()
+
+	v, err := Attributes(nil).Value()
+	require.NoError(t, err)
+	require.Equal(t, "{}", v)
+
+	attrs := Attributes{"connection_type": "cable", "isp": "Telekom Slovenije"}
+
+	v, err = attrs.Value()
+	require.NoError(t, err)
+	require.JSONEq(t, `{"connection_type": "cable", "isp": "Telekom Slovenije"}`, v.(string))
+
+	var scanned Attributes
+
+	require.NoError(t, scanned.Scan([]byte(v.(string))))
+	require.Equal(t, attrs, scanned)
+
+	require.NoError(t, scanned.Scan("{}"))
+	require.Nil(t, scanned)
+
+	require.Error(t, scanned.Scan(42))
+	require.Error(t, scanned.Scan("[]"))
+}
+
+func TestGeolocation_Diff_attributes(t *testing.T) {
+	t.Parallel()
+
+	g := Geolocation{Attributes: Attributes{"connection_type": "cable", "isp": "Telekom Slovenije"}}
+	o := Geolocation{Attributes: Attributes{"isp": "A1 Slovenija", "usage_type": "isp"}}
+
+	require.Equal(t, []FieldChange{
+		{Field: "attributes.connection_type", Old: "cable", New: ""},
+		{Field: "attributes.isp", Old: "Telekom Slovenije", New: "A1 Slovenija"},
+		{Field: "attributes.usage_type", Old: "", New: "isp"},
+	}, g.Diff(o))
+}
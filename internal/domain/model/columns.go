@@ -0,0 +1,169 @@
+package model
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bool64/ctxd"
+)
+
+// Column names of the input data.
+const (
+	ColIPAddress      = "ip_address"
+	ColCountryCode    = "country_code"
+	ColCountry        = "country"
+	ColCity           = "city"
+	ColLatitude       = "latitude"
+	ColLongitude      = "longitude"
+	ColMysteryValue   = "mystery_value"
+	ColRegion         = "region"
+	ColPostalCode     = "postal_code"
+	ColTimeZone       = "time_zone"
+	ColASN            = "asn"
+	ColOrganization   = "organization"
+	ColAccuracyRadius = "accuracy_radius"
+)
+
+// requiredColumns are the columns every input data has, in the order of the legacy header-less input.
+var requiredColumns = []string{
+	ColIPAddress,
+	ColCountryCode,
+	ColCountry,
+	ColCity,
+	ColLatitude,
+	ColLongitude,
+	ColMysteryValue,
+}
+
+// knownColumns are the columns mapped to a field of the geolocation data, the other columns are kept as attributes.
+var knownColumns = map[string]struct{}{
+	ColIPAddress:      {},
+	ColCountryCode:    {},
+	ColCountry:        {},
+	ColCity:           {},
+	ColLatitude:       {},
+	ColLongitude:      {},
+	ColMysteryValue:   {},
+	ColRegion:         {},
+	ColPostalCode:     {},
+	ColTimeZone:       {},
+	ColASN:            {},
+	ColOrganization:   {},
+	ColAccuracyRadius: {},
+}
+
+// columnAliases are the other names the columns are known by.
+var columnAliases = map[string]string{
+	"ip":           ColIPAddress,
+	"subdivision":  ColRegion,
+	"zip":          ColPostalCode,
+	"zip_code":     ColPostalCode,
+	"timezone":     ColTimeZone,
+	"as_number":    ColASN,
+	"org":          ColOrganization,
+	"as_org":       ColOrganization,
+	"accuracy":     ColAccuracyRadius,
+	"accuracy_km":  ColAccuracyRadius,
+	"country_name": ColCountry,
+}
+
+// Columns maps the column names of the input data to their position.
+type Columns struct {
+	index map[string]int
+	// attributes are the columns not mapped to a field.
+	attributes []string
+	n          int
+}
+
+// defaultColumns maps the legacy header-less input data.
+var defaultColumns = mustColumns(requiredColumns)
+
+func mustColumns(header []string) *Columns {
+	c, err := NewColumns(header)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// NewColumns maps the columns of the header of the input data.
+//
+// The names are case-insensitive and can be any of the known aliases, i.e. timezone for time_zone. The unknown columns
+// are decoded as Attributes, named in lower case with spaces replaced by underscores. Returns an error when a required
+// column is missing or a column is repeated.
+func NewColumns(header []string) (*Columns, error) {
+	c := &Columns{
+		index: make(map[string]int, len(header)),
+		n:     len(header),
+	}
+
+	for i, name := range header {
+		name = normalizeColumn(name)
+
+		if _, ok := c.index[name]; ok {
+			return nil, ctxd.NewError(context.Background(), "duplicate column", "column", name)
+		}
+
+		c.index[name] = i
+
+		if _, ok := knownColumns[name]; !ok {
+			c.attributes = append(c.attributes, name)
+		}
+	}
+
+	for _, name := range requiredColumns {
+		if _, ok := c.index[name]; !ok {
+			return nil, ctxd.NewError(context.Background(), "missing column", "column", name)
+		}
+	}
+
+	return c, nil
+}
+
+// normalizeColumn returns the canonical name of the column.
+func normalizeColumn(name string) string {
+	name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+	name = strings.ReplaceAll(name, " ", "_")
+
+	if alias, ok := columnAliases[name]; ok {
+		return alias
+	}
+
+	return name
+}
+
+// Len returns the number of columns.
+func (c *Columns) Len() int {
+	return c.n
+}
+
+// value returns the value of the column in the record, empty when the input data has no such column.
+func (c *Columns) value(record []string, name string) string {
+	i, ok := c.index[name]
+	if !ok {
+		return ""
+	}
+
+	return record[i]
+}
+
+// attributeValues returns the values of the columns not mapped to a field, nil when all of them are empty.
+func (c *Columns) attributeValues(record []string) Attributes {
+	var attrs Attributes
+
+	for _, name := range c.attributes {
+		v := record[c.index[name]]
+		if v == "" {
+			continue
+		}
+
+		if attrs == nil {
+			attrs = make(Attributes, len(c.attributes))
+		}
+
+		attrs[name] = v
+	}
+
+	return attrs
+}
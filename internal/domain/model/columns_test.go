@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/dohernandez/vio/internal/platform/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewColumns(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewColumns([]string{"ip_address", "country_code", "country", "city", "latitude", "longitude"})
+	require.EqualError(t, err, "missing column")
+
+	_, err = NewColumns([]string{"ip", "ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value"})
+	require.EqualError(t, err, "duplicate column")
+
+	c, err := NewColumns([]string{"\ufeffIP", "Country Code", "country", "city", "latitude", "longitude", "mystery_value", "TimeZone"})
+	require.NoError(t, err)
+	require.Equal(t, 8, c.Len())
+}
+
+func TestDecodeGeolocation_columns(t *testing.T) {
+	t.Parallel()
+
+	data, err := helpers.LoadExtendedSampleData()
+	require.NoError(t, err)
+
+	columns, err := NewColumns(data[0])
+	require.NoError(t, err)
+
+	geo, err := DecodeGeolocation(data[1], WithColumns(columns))
+	require.NoError(t, err)
+	require.NoError(t, geo.IsValid())
+
+	require.Equal(t, Geolocation{
+		IPAddress:      "200.106.141.15",
+		CountryCode:    "SI",
+		Country:        "Slovenia",
+		City:           "Ljubljana",
+		Latitude:       46.0569,
+		Longitude:      14.5058,
+		MysteryValue:   7823011346,
+		Region:         "Ljubljana",
+		PostalCode:     "1000",
+		TimeZone:       "Europe/Ljubljana",
+		ASN:            2108,
+		Organization:   "Telekom Slovenije",
+		AccuracyRadius: 20,
+		Attributes:     Attributes{"connection_type": "cable"},
+	}, geo)
+
+	// The optional columns can be empty.
+	geo, err = DecodeGeolocation(data[2], WithColumns(columns))
+	require.NoError(t, err)
+	require.NoError(t, geo.IsValid())
+	require.Empty(t, geo.Region)
+	require.Zero(t, geo.ASN)
+	require.Nil(t, geo.Attributes)
+
+	// Unknown time zone.
+	geo, err = DecodeGeolocation(data[4], WithColumns(columns))
+	require.NoError(t, err)
+
+	var verr *ValidationError
+
+	require.ErrorAs(t, geo.IsValid(), &verr)
+	require.Equal(t, CodeInvalidTimeZone, verr.Code)
+
+	// Invalid ASN.
+	record := append([]string(nil), data[3]...)
+	record[10] = "ASX"
+
+	_, err = DecodeGeolocation(record, WithColumns(columns))
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, CodeInvalidASN, verr.Code)
+
+	// The records must have as many fields as the header.
+	_, err = DecodeGeolocation(data[1][:7], WithColumns(columns))
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, CodeInvalidFieldCount, verr.Code)
+}
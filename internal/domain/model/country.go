@@ -0,0 +1,133 @@
+package model
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"strings"
+	"sync"
+)
+
+// CountryPolicy defines how the country name is checked against the country code.
+type CountryPolicy string
+
+const (
+	// CountryLenient accepts any country name, only the country code is validated.
+	CountryLenient CountryPolicy = "lenient"
+	// CountryStrict rejects the geolocation data when the country name does not match the country code.
+	CountryStrict CountryPolicy = "strict"
+	// CountryCorrect replaces the country name with the one of the country code.
+	CountryCorrect CountryPolicy = "correct"
+)
+
+// iso3166 is the ISO 3166-1 table, with the alpha-2 code, the English short name and the names also accepted for
+// the country separated by '|'.
+//
+//go:embed iso3166.csv
+var iso3166 string
+
+type country struct {
+	name string
+	// names are the normalized names accepted for the country.
+	names map[string]struct{}
+}
+
+var (
+	countriesOnce sync.Once
+	countries     map[string]country
+)
+
+func loadCountries() map[string]country {
+	countriesOnce.Do(func() {
+		records, err := csv.NewReader(strings.NewReader(iso3166)).ReadAll()
+		if err != nil {
+			panic("model: invalid embedded ISO 3166-1 table: " + err.Error())
+		}
+
+		countries = make(map[string]country, len(records))
+
+		// Skip the header.
+		for _, rec := range records[1:] {
+			c := country{
+				name:  rec[1],
+				names: map[string]struct{}{normalizeCountryName(rec[1]): {}},
+			}
+
+			if rec[2] != "" {
+				for _, alias := range strings.Split(rec[2], "|") {
+					c.names[normalizeCountryName(alias)] = struct{}{}
+				}
+			}
+
+			countries[rec[0]] = c
+		}
+	})
+
+	return countries
+}
+
+// normalizeCountryName lower-cases the name, dropping punctuation and the differences in spacing. St is read as
+// Saint.
+func normalizeCountryName(name string) string {
+	name = strings.ReplaceAll(strings.ToLower(name), "&", " and ")
+
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '.', ',', '(', ')', '\'', '-':
+			return ' '
+		}
+
+		return r
+	}, name)
+
+	words := strings.Fields(name)
+
+	for i, w := range words {
+		if w == "st" {
+			words[i] = "saint"
+		}
+	}
+
+	return strings.Join(words, " ")
+}
+
+// CountryName returns the ISO 3166-1 English short name of the country code.
+//
+// Returns false when the code is not an ISO 3166-1 alpha-2 code.
+func CountryName(code string) (string, bool) {
+	c, ok := loadCountries()[code]
+
+	return c.name, ok
+}
+
+// IsCountryCode reports whether the code is an ISO 3166-1 alpha-2 code.
+func IsCountryCode(code string) bool {
+	_, ok := loadCountries()[code]
+
+	return ok
+}
+
+// checkCountry checks the country name of the geolocation against its country code according to the policy.
+//
+// Unknown country codes are left to IsValid.
+func (g *Geolocation) checkCountry(policy CountryPolicy) error {
+	if policy != CountryStrict && policy != CountryCorrect {
+		return nil
+	}
+
+	c, ok := loadCountries()[g.CountryCode]
+	if !ok {
+		return nil
+	}
+
+	if policy == CountryCorrect {
+		g.Country = c.name
+
+		return nil
+	}
+
+	if _, ok := c.names[normalizeCountryName(g.Country)]; !ok {
+		return NewValidationError("country", CodeCountryNameMismatch, g.Country)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/dohernandez/vio/internal/platform/helpers"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountryName(t *testing.T) {
+	t.Parallel()
+
+	name, ok := CountryName("SI")
+	require.True(t, ok)
+	require.Equal(t, "Slovenia", name)
+
+	_, ok = CountryName("XX")
+	require.False(t, ok)
+
+	require.True(t, IsCountryCode("NP"))
+	require.False(t, IsCountryCode("np"))
+}
+
+func TestDecodeGeolocation_country_policy(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	// 200.106.141.15,SI,Nepal,DuBuquemouth,-84.87503094689836,7.206435933364332,7823011346
+	data, err := helpers.LoadSampleData(1, 0)
+	require.NoError(t, err)
+
+	geolocation, err := DecodeGeolocation(data[0])
+	require.NoError(t, err)
+	require.Equal(t, "Nepal", geolocation.Country)
+
+	geolocation, err = DecodeGeolocation(data[0], WithCountryPolicy(CountryLenient))
+	require.NoError(t, err)
+	require.Equal(t, "Nepal", geolocation.Country)
+
+	_, err = DecodeGeolocation(data[0], WithCountryPolicy(CountryStrict))
+	require.EqualError(t, err, "country name mismatch")
+
+	geolocation, err = DecodeGeolocation(data[0], WithCountryPolicy(CountryCorrect))
+	require.NoError(t, err)
+	require.Equal(t, "Slovenia", geolocation.Country)
+}
+
+func TestGeolocation_checkCountry_aliases(t *testing.T) {
+	t.Parallel()
+
+	for code, name := range map[string]string{
+		"SI": "slovenia",
+		"CZ": "Czech Republic",
+		"FK": "Falkland Islands (Malvinas)",
+		"KN": "St Kitts & Nevis",
+		"CI": "Cote d'Ivoire",
+		"US": "United States",
+	} {
+		geo := Geolocation{CountryCode: code, Country: name}
+
+		require.NoError(t, geo.checkCountry(CountryStrict), name)
+	}
+
+	// Unknown country codes are left to IsValid.
+	geo := Geolocation{CountryCode: "XX", Country: "Nowhere"}
+	require.NoError(t, geo.checkCountry(CountryStrict))
+}
+
+func TestGeolocation_IsValid_unknown_country_code(t *testing.T) {
+	t.Parallel()
+
+	geo := Geolocation{
+		IPAddress:   "200.106.141.15",
+		CountryCode: "XX",
+		Country:     "Nepal",
+		City:        "DuBuquemouth",
+	}
+
+	require.EqualError(t, geo.IsValid(), "unknown country code")
+}
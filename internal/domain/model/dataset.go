@@ -0,0 +1,23 @@
+package model
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/bool64/ctxd"
+)
+
+// DefaultDataset is the dataset of the geolocation data imported without naming one.
+const DefaultDataset = "default"
+
+// datasetName is the format of the dataset names, i.e. the name of the provider.
+var datasetName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// CheckDatasetName checks the dataset name is made of up to 64 lower case letters, digits, '_' and '-'.
+func CheckDatasetName(name string) error {
+	if !datasetName.MatchString(name) {
+		return ctxd.NewError(context.Background(), "invalid dataset name", "dataset", name)
+	}
+
+	return nil
+}
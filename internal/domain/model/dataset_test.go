@@ -0,0 +1,19 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckDatasetName(t *testing.T) {
+	t.Parallel()
+
+	for _, name := range []string{DefaultDataset, "maxmind", "ip2location-lite", "provider_2"} {
+		require.NoError(t, CheckDatasetName(name), name)
+	}
+
+	for _, name := range []string{"", "MaxMind", "-provider", "provider a", "provider/a", string(make([]byte, 65))} {
+		require.Error(t, CheckDatasetName(name), name)
+	}
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"strings"
+)
+
+// ErrorCode is the stable code of a ValidationError, meant to aggregate and match validation errors.
+type ErrorCode string
+
+// Validation error codes.
+const (
+	CodeInvalidFieldCount        ErrorCode = "invalid_field_count"
+	CodeMissingIPAddress         ErrorCode = "missing_ip_address"
+	CodeInvalidIPAddress         ErrorCode = "invalid_ip_address"
+	CodeDuplicateIPAddress       ErrorCode = "duplicate_ip_address"
+	CodeBlocklistedIPAddress     ErrorCode = "blocklisted_ip_address"
+	CodeMissingCountryCode       ErrorCode = "missing_country_code"
+	CodeInvalidCountryCodeLength ErrorCode = "invalid_country_code_length"
+	CodeUnknownCountryCode       ErrorCode = "unknown_country_code"
+	CodeMissingCountry           ErrorCode = "missing_country"
+	CodeCountryNameMismatch      ErrorCode = "country_name_mismatch"
+	CodeMissingCity              ErrorCode = "missing_city"
+	CodeInvalidLatitude          ErrorCode = "invalid_latitude"
+	CodeInvalidLongitude         ErrorCode = "invalid_longitude"
+	CodeZeroCoordinates          ErrorCode = "zero_coordinates"
+	CodeInvalidMysteryValue      ErrorCode = "invalid_mystery_value"
+	CodeFractionalMysteryValue   ErrorCode = "fractional_mystery_value"
+	CodeMysteryValueOutOfRange   ErrorCode = "mystery_value_out_of_range"
+	CodeInvalidASN               ErrorCode = "invalid_asn"
+	CodeInvalidAccuracyRadius    ErrorCode = "invalid_accuracy_radius"
+	CodeInvalidTimeZone          ErrorCode = "invalid_time_zone"
+	CodeUnspecifiedIPAddress     ErrorCode = "unspecified_ip_address"
+	CodeLoopbackIPAddress        ErrorCode = "loopback_ip_address"
+	CodePrivateIPAddress         ErrorCode = "private_ip_address"
+	CodeLinkLocalIPAddress       ErrorCode = "link_local_ip_address"
+	CodeMulticastIPAddress       ErrorCode = "multicast_ip_address"
+	CodeDocumentationIPAddress   ErrorCode = "documentation_ip_address"
+	CodeReservedIPAddress        ErrorCode = "reserved_ip_address"
+)
+
+// ValidationError is the error of the geolocation data failing to be decoded or validated.
+//
+// Use errors.As to get the field, code and value of the error.
+type ValidationError struct {
+	// Field is the name of the field, as in the input data. Empty when the error is not about a single field.
+	Field string
+	Code  ErrorCode
+	// Value is the offending value.
+	Value any
+}
+
+// NewValidationError creates a ValidationError.
+func NewValidationError(field string, code ErrorCode, value any) *ValidationError {
+	return &ValidationError{
+		Field: field,
+		Code:  code,
+		Value: value,
+	}
+}
+
+// Error returns the human-readable form of the code, i.e. "invalid latitude" for CodeInvalidLatitude.
+func (e *ValidationError) Error() string {
+	return strings.ReplaceAll(string(e.Code), "_", " ")
+}
+
+// Tuples implements ctxd.StructuredError, so the field and value are logged.
+func (e *ValidationError) Tuples() []interface{} {
+	tuples := make([]interface{}, 0, 4)
+
+	if e.Field != "" {
+		tuples = append(tuples, "field", e.Field)
+	}
+
+	if e.Value != nil {
+		tuples = append(tuples, "value", e.Value)
+	}
+
+	return tuples
+}
+
+// Fields implements ctxd.StructuredError.
+func (e *ValidationError) Fields() map[string]interface{} {
+	fields := make(map[string]interface{}, 2)
+
+	if e.Field != "" {
+		fields["field"] = e.Field
+	}
+
+	if e.Value != nil {
+		fields["value"] = e.Value
+	}
+
+	return fields
+}
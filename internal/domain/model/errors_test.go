@@ -0,0 +1,37 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidationError(t *testing.T) {
+	t.Parallel()
+
+	geo := Geolocation{
+		IPAddress:   "200.106.141.15",
+		CountryCode: "SI",
+		Country:     "Nepal",
+		City:        "DuBuquemouth",
+		Latitude:    -95,
+	}
+
+	err := fmt.Errorf("wrapped: %w", geo.IsValid())
+
+	var verr *ValidationError
+
+	require.True(t, errors.As(err, &verr))
+	require.Equal(t, "latitude", verr.Field)
+	require.Equal(t, CodeInvalidLatitude, verr.Code)
+	require.InDelta(t, -95.0, verr.Value, 0)
+	require.Equal(t, "invalid latitude", verr.Error())
+
+	var serr ctxd.StructuredError
+
+	require.True(t, errors.As(err, &serr))
+	require.Equal(t, map[string]interface{}{"field": "latitude", "value": -95.0}, serr.Fields())
+}
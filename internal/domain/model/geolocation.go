@@ -1,17 +1,19 @@
 package model
 
 import (
-	"context"
 	"errors"
-	"net"
+	"math"
 	"strconv"
-
-	"github.com/bool64/ctxd"
+	"strings"
 )
 
 const (
-	// InputFieldNum is the number of fields in the input data. It is used to validate the input data before normalizing.
+	// InputFieldNum is the number of fields in the header-less input data. It is used to validate the input data
+	// before normalizing.
 	InputFieldNum = 7
+
+	// coordinateEpsilon is the tolerance comparing coordinates, the storage keeps up to 15 decimals.
+	coordinateEpsilon = 1e-12
 )
 
 // Geolocation errors.
@@ -23,81 +25,260 @@ var (
 
 // Geolocation represents a geolocation entity.
 type Geolocation struct {
-	IPAddress    string  `db:"ip_address"`
-	CountryCode  string  `db:"country_code"`
-	Country      string  `db:"country"`
-	City         string  `db:"city"`
-	Latitude     float64 `db:"latitude"`
-	Longitude    float64 `db:"longitude"`
-	MysteryValue float64 `db:"mystery_value"`
+	IPAddress    string  `db:"ip_address" json:"ip_address"`
+	CountryCode  string  `db:"country_code" json:"country_code"`
+	Country      string  `db:"country" json:"country"`
+	City         string  `db:"city" json:"city"`
+	Latitude     float64 `db:"latitude" json:"latitude"`
+	Longitude    float64 `db:"longitude" json:"longitude"`
+	MysteryValue int64   `db:"mystery_value" json:"mystery_value"`
+
+	// Optional fields, empty or zero when unknown.
+
+	// Region is the subdivision of the country, i.e. state or province.
+	Region     string `db:"region" json:"region,omitempty"`
+	PostalCode string `db:"postal_code" json:"postal_code,omitempty"`
+	// TimeZone is the IANA time zone, i.e. Europe/Ljubljana.
+	TimeZone string `db:"time_zone" json:"time_zone,omitempty"`
+	// ASN is the autonomous system number.
+	ASN          uint32 `db:"asn" json:"asn,omitempty"`
+	Organization string `db:"organization" json:"organization,omitempty"`
+	// AccuracyRadius is the radius in kilometers around the coordinates where the IP address is likely to be.
+	AccuracyRadius uint32 `db:"accuracy_radius" json:"accuracy_radius,omitempty"`
+	// Attributes are the extra attributes, from the columns of the input data not mapped to a field.
+	Attributes Attributes `db:"attributes" json:"attributes,omitempty"`
+
+	// VersionID is the dataset version which last inserted or changed the geolocation data, zero when unknown.
+	VersionID int64 `db:"version_id" json:"version_id,omitempty"`
+	// Dataset is the name of the dataset the geolocation data belongs to, i.e. the provider.
+	Dataset string `db:"dataset" json:"dataset,omitempty"`
+
+	// Provenance of the geolocation data, the run importing it is the dataset version.
+
+	// SourceFile is the name of the source the geolocation data was read from.
+	SourceFile string `db:"source_file" json:"source_file,omitempty"`
+	// SourceLine is the line of the geolocation data in the source, the header being the first line.
+	SourceLine uint64 `db:"source_line" json:"source_line,omitempty"`
+}
+
+// decodeOptions configures DecodeGeolocation.
+type decodeOptions struct {
+	columns    *Columns
+	mappedIPv4 MappedIPv4Policy
+	country    CountryPolicy
+	fraction   FractionPolicy
+}
+
+// DecodeOption sets up DecodeGeolocation.
+type DecodeOption func(o *decodeOptions)
+
+// WithColumns sets the columns of the input data, mapped from its header. Defaults to the 7 required columns, in the
+// order of the legacy header-less input: ip_address, country_code, country, city, latitude, longitude and
+// mystery_value.
+func WithColumns(columns *Columns) DecodeOption {
+	return func(o *decodeOptions) {
+		if columns != nil {
+			o.columns = columns
+		}
+	}
+}
+
+// WithMappedIPv4Policy sets how IPv4-mapped IPv6 addresses are canonicalised. Defaults to UnmapIPv4.
+func WithMappedIPv4Policy(policy MappedIPv4Policy) DecodeOption {
+	return func(o *decodeOptions) {
+		o.mappedIPv4 = policy
+	}
+}
+
+// DecodeMappedIPv4Policy returns how the options canonicalise IPv4-mapped IPv6 addresses.
+func DecodeMappedIPv4Policy(opts ...DecodeOption) MappedIPv4Policy {
+	var o decodeOptions
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if o.mappedIPv4 == "" {
+		return UnmapIPv4
+	}
+
+	return o.mappedIPv4
+}
+
+// WithCountryPolicy sets how the country name is checked against the country code. Defaults to CountryLenient.
+func WithCountryPolicy(policy CountryPolicy) DecodeOption {
+	return func(o *decodeOptions) {
+		o.country = policy
+	}
+}
+
+// WithFractionPolicy sets how a fractional mystery value is handled. Defaults to FractionReject.
+func WithFractionPolicy(policy FractionPolicy) DecodeOption {
+	return func(o *decodeOptions) {
+		o.fraction = policy
+	}
 }
 
 // DecodeGeolocation normalizes the input data into a geolocation entity.
-func DecodeGeolocation(data []string) (Geolocation, error) {
+//
+// The IP address is canonicalised, see CanonicalIPAddress. An invalid IP address is kept as it is, to be reported
+// by IsValid. The mystery value must be an integer, a fractional one is rejected or rounded according to the
+// FractionPolicy. The country name is checked or corrected according to the CountryPolicy. The values of the columns not
+// mapped to a field are kept as Attributes.
+func DecodeGeolocation(data []string, opts ...DecodeOption) (Geolocation, error) {
 	var geo Geolocation
 
-	if len(data) != InputFieldNum {
-		return geo, ctxd.NewError(context.Background(), "not enough fields in input", "input", data, "expected", InputFieldNum, "actual", len(data))
+	o := decodeOptions{
+		columns:    defaultColumns,
+		mappedIPv4: UnmapIPv4,
+		country:    CountryLenient,
+		fraction:   FractionReject,
 	}
 
-	geo.IPAddress = data[0]
-	geo.CountryCode = data[1]
-	geo.Country = data[2]
-	geo.City = data[3]
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	if len(data) != o.columns.Len() {
+		return geo, NewValidationError("", CodeInvalidFieldCount, len(data))
+	}
+
+	col := func(name string) string {
+		return o.columns.value(data, name)
+	}
+
+	geo.IPAddress = col(ColIPAddress)
+
+	if ip, err := CanonicalIPAddress(geo.IPAddress, o.mappedIPv4); err == nil {
+		geo.IPAddress = ip
+	}
+
+	geo.CountryCode = col(ColCountryCode)
+	geo.Country = col(ColCountry)
+	geo.City = col(ColCity)
+	geo.Region = col(ColRegion)
+	geo.PostalCode = col(ColPostalCode)
+	geo.TimeZone = col(ColTimeZone)
+	geo.Organization = col(ColOrganization)
+	geo.Attributes = o.columns.attributeValues(data)
 
 	var err error
 
-	geo.Latitude, err = strconv.ParseFloat(data[4], 64)
+	geo.Latitude, err = strconv.ParseFloat(col(ColLatitude), 64)
 	if err != nil {
-		return geo, ctxd.NewError(context.Background(), "parsing latitude", "latitude", data[4], "error", err)
+		return geo, NewValidationError(ColLatitude, CodeInvalidLatitude, col(ColLatitude))
 	}
 
-	geo.Longitude, err = strconv.ParseFloat(data[5], 64)
+	geo.Longitude, err = strconv.ParseFloat(col(ColLongitude), 64)
 	if err != nil {
-		return geo, ctxd.NewError(context.Background(), "parsing longitude", "longitude", data[5], "error", err)
+		return geo, NewValidationError(ColLongitude, CodeInvalidLongitude, col(ColLongitude))
 	}
 
-	geo.MysteryValue, err = strconv.ParseFloat(data[6], 64)
+	geo.MysteryValue, err = parseMysteryValue(col(ColMysteryValue), o.fraction)
 	if err != nil {
-		return geo, ctxd.NewError(context.Background(), "parsing mystery value", "mystery_value", data[6], "error", err)
+		return geo, err
 	}
 
-	return geo, nil
-}
+	if v := col(ColASN); v != "" {
+		asn, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(v), "AS"), 10, 32)
+		if err != nil {
+			return geo, NewValidationError(ColASN, CodeInvalidASN, v)
+		}
 
-// IsValid validates the geolocation entity.
-func (g Geolocation) IsValid() error {
-	if g.IPAddress == "" {
-		return ctxd.NewError(context.Background(), "missing ip address")
+		geo.ASN = uint32(asn)
 	}
 
-	if net.ParseIP(g.IPAddress) == nil {
-		return ctxd.NewError(context.Background(), "invalid ip address", "ip_address", g.IPAddress)
-	}
+	if v := col(ColAccuracyRadius); v != "" {
+		radius, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return geo, NewValidationError(ColAccuracyRadius, CodeInvalidAccuracyRadius, v)
+		}
 
-	if g.CountryCode == "" {
-		return ctxd.NewError(context.Background(), "missing country code")
+		geo.AccuracyRadius = uint32(radius)
 	}
 
-	if len(g.CountryCode) != 2 {
-		return ctxd.NewError(context.Background(), "invalid country code length", "country_code", g.CountryCode)
+	if err := geo.checkCountry(o.country); err != nil {
+		return geo, err
 	}
 
-	if g.Country == "" {
-		return ctxd.NewError(context.Background(), "missing country")
+	return geo, nil
+}
+
+// IsValid validates the geolocation entity against the DefaultRuleSet.
+func (g Geolocation) IsValid() error {
+	return defaultRuleSet.Check(g)
+}
+
+// FieldChange describes the change of a geolocation field.
+type FieldChange struct {
+	Field string `json:"field" yaml:"field"`
+	Old   string `json:"old" yaml:"old"`
+	New   string `json:"new" yaml:"new"`
+}
+
+// Diff returns the fields changed from the geolocation entity to o.
+//
+// Coordinates are compared with a tolerance, to absorb the rounding of the storage.
+func (g Geolocation) Diff(o Geolocation) []FieldChange {
+	var changes []FieldChange
+
+	diffString := func(field, from, to string) {
+		if from != to {
+			changes = append(changes, FieldChange{Field: field, Old: from, New: to})
+		}
 	}
 
-	if g.City == "" {
-		return ctxd.NewError(context.Background(), "missing city")
+	diffFloat := func(field string, from, to, epsilon float64) {
+		if math.Abs(from-to) > epsilon {
+			changes = append(changes, FieldChange{
+				Field: field,
+				Old:   strconv.FormatFloat(from, 'f', -1, 64),
+				New:   strconv.FormatFloat(to, 'f', -1, 64),
+			})
+		}
 	}
 
-	if g.Latitude < -90 || g.Latitude > 90 {
-		return ctxd.NewError(context.Background(), "invalid latitude", "latitude", g.Latitude)
+	diffString("country_code", g.CountryCode, o.CountryCode)
+	diffString("country", g.Country, o.Country)
+	diffString("city", g.City, o.City)
+	diffFloat("latitude", g.Latitude, o.Latitude, coordinateEpsilon)
+	diffFloat("longitude", g.Longitude, o.Longitude, coordinateEpsilon)
+	diffString("mystery_value", strconv.FormatInt(g.MysteryValue, 10), strconv.FormatInt(o.MysteryValue, 10))
+	diffString("region", g.Region, o.Region)
+	diffString("postal_code", g.PostalCode, o.PostalCode)
+	diffString("time_zone", g.TimeZone, o.TimeZone)
+	diffString("asn", strconv.FormatUint(uint64(g.ASN), 10), strconv.FormatUint(uint64(o.ASN), 10))
+	diffString("organization", g.Organization, o.Organization)
+	diffString("accuracy_radius", strconv.FormatUint(uint64(g.AccuracyRadius), 10), strconv.FormatUint(uint64(o.AccuracyRadius), 10))
+
+	for _, name := range mergeNames(g.Attributes.Names(), o.Attributes.Names()) {
+		diffString("attributes."+name, g.Attributes[name], o.Attributes[name])
 	}
 
-	if g.Longitude < -180 || g.Longitude > 180 {
-		return ctxd.NewError(context.Background(), "invalid longitude", "longitude", g.Longitude)
+	return changes
+}
+
+// mergeNames merges the sorted names a and b, without repetitions.
+func mergeNames(a, b []string) []string {
+	names := make([]string, 0, len(a)+len(b))
+
+	for len(a) > 0 || len(b) > 0 {
+		switch {
+		case len(b) == 0 || (len(a) > 0 && a[0] < b[0]):
+			names, a = append(names, a[0]), a[1:]
+		case len(a) == 0 || b[0] < a[0]:
+			names, b = append(names, b[0]), b[1:]
+		default:
+			names, a, b = append(names, a[0]), a[1:], b[1:]
+		}
 	}
 
-	return nil
+	return names
+}
+
+// Equal reports whether the geolocation entity holds the same data as o.
+//
+// Coordinates are compared with a tolerance, to absorb the rounding of the storage.
+func (g Geolocation) Equal(o Geolocation) bool {
+	return g.IPAddress == o.IPAddress && len(g.Diff(o)) == 0
 }
@@ -27,6 +27,43 @@ func TestDecodeGeolocation_success(t *testing.T) {
 	require.InEpsilon(t, float64(7823011346), geolocation.MysteryValue, 0)
 }
 
+func TestDecodeGeolocation_ipv6(t *testing.T) {
+	t.Parallel()
+
+	data, err := helpers.LoadIPv6SampleData()
+	require.NoError(t, err)
+
+	var ips []string
+
+	for _, d := range data {
+		geolocation, err := DecodeGeolocation(d)
+		require.NoError(t, err)
+
+		ips = append(ips, geolocation.IPAddress)
+	}
+
+	// The zoned address is kept as it is, to be reported as invalid.
+	require.Equal(t, []string{
+		"2001:db8::1",
+		"2001:db8::1",
+		"70.95.73.73",
+		"70.95.73.73",
+		"fe80::1%eth0",
+		"2001:db8:85a3::8a2e:370:7334",
+	}, ips)
+
+	geolocation, err := DecodeGeolocation(data[2], WithMappedIPv4Policy(KeepMappedIPv4))
+	require.NoError(t, err)
+	require.Equal(t, "::ffff:70.95.73.73", geolocation.IPAddress)
+
+	require.Error(t, Geolocation{
+		IPAddress:   "fe80::1%eth0",
+		CountryCode: "PY",
+		Country:     "Falkland Islands (Malvinas)",
+		City:        "Port Stanley",
+	}.IsValid())
+}
+
 func TestDecodeGeolocation_error_no_enough(t *testing.T) {
 	t.Parallel()
 
@@ -53,7 +90,12 @@ func TestDecodeGeolocation_error_latitude(t *testing.T) {
 
 	_, err = DecodeGeolocation(data[0])
 	require.Error(t, err)
-	require.ErrorContains(t, err, "parsing latitude")
+	var verr *ValidationError
+
+	require.ErrorAs(t, err, &verr)
+	require.Equal(t, CodeInvalidLatitude, verr.Code)
+	require.Equal(t, "latitude", verr.Field)
+	require.Equal(t, "", verr.Value)
 }
 
 func TestDecodeGeolocation_error_longitude(t *testing.T) {
@@ -69,7 +111,7 @@ func TestDecodeGeolocation_error_longitude(t *testing.T) {
 
 	_, err = DecodeGeolocation(data[0])
 	require.Error(t, err)
-	require.ErrorContains(t, err, "parsing longitude")
+	require.ErrorContains(t, err, "invalid longitude")
 }
 
 func TestGeolocation_IsValid_success(t *testing.T) {
@@ -101,3 +143,47 @@ func TestGeolocation_IsValid_failure(t *testing.T) {
 	require.Error(t, err)
 	require.ErrorContains(t, err, "missing ip address")
 }
+
+func TestGeolocation_Equal(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	// 200.106.141.15,SI,Nepal,DuBuquemouth,-84.87503094689836,7.206435933364332,7823011346
+	data, err := helpers.LoadSampleData(1, 0)
+	require.NoError(t, err)
+
+	geolocation, err := DecodeGeolocation(data[0])
+	require.NoError(t, err)
+
+	stored := geolocation
+	stored.Latitude = -84.875030946898360000001
+
+	require.True(t, geolocation.Equal(stored))
+
+	stored.City = "New Neva"
+
+	require.False(t, geolocation.Equal(stored))
+}
+
+func TestGeolocation_Diff(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	// 200.106.141.15,SI,Nepal,DuBuquemouth,-84.87503094689836,7.206435933364332,7823011346
+	data, err := helpers.LoadSampleData(1, 0)
+	require.NoError(t, err)
+
+	geolocation, err := DecodeGeolocation(data[0])
+	require.NoError(t, err)
+
+	require.Empty(t, geolocation.Diff(geolocation))
+
+	candidate := geolocation
+	candidate.City = "New Neva"
+	candidate.Latitude = -68.31023296602508
+
+	require.Equal(t, []FieldChange{
+		{Field: "city", Old: "DuBuquemouth", New: "New Neva"},
+		{Field: "latitude", Old: "-84.87503094689836", New: "-68.31023296602508"},
+	}, geolocation.Diff(candidate))
+}
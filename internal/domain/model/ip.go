@@ -0,0 +1,40 @@
+package model
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/bool64/ctxd"
+)
+
+// MappedIPv4Policy defines how IPv4-mapped IPv6 addresses, i.e. ::ffff:1.2.3.4, are canonicalised.
+type MappedIPv4Policy string
+
+const (
+	// UnmapIPv4 canonicalises IPv4-mapped IPv6 addresses as the plain IPv4 address, ::ffff:1.2.3.4 becomes 1.2.3.4.
+	UnmapIPv4 MappedIPv4Policy = "unmap"
+	// KeepMappedIPv4 keeps IPv4-mapped IPv6 addresses as they are, ::ffff:1.2.3.4 stays ::ffff:1.2.3.4.
+	KeepMappedIPv4 MappedIPv4Policy = "keep"
+)
+
+// CanonicalIPAddress returns the canonical text form of the IP address.
+//
+// IPv6 addresses are lower-cased and zero-compressed (RFC 5952), so 2001:DB8::1 and 2001:db8:0:0::1 become
+// 2001:db8::1. IPv4-mapped IPv6 addresses are handled according to policy. Addresses with a zone are rejected, the
+// zone has no meaning outside the host.
+func CanonicalIPAddress(ip string, policy MappedIPv4Policy) (string, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return "", ctxd.WrapError(context.Background(), err, "invalid ip address", "ip_address", ip)
+	}
+
+	if addr.Zone() != "" {
+		return "", ctxd.NewError(context.Background(), "ip address with zone", "ip_address", ip)
+	}
+
+	if policy != KeepMappedIPv4 {
+		addr = addr.Unmap()
+	}
+
+	return addr.String(), nil
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanonicalIPAddress(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		ip       string
+		policy   MappedIPv4Policy
+		expected string
+	}{
+		{ip: "200.106.141.15", policy: UnmapIPv4, expected: "200.106.141.15"},
+		{ip: "2001:DB8::1", policy: UnmapIPv4, expected: "2001:db8::1"},
+		{ip: "2001:db8:0:0::1", policy: UnmapIPv4, expected: "2001:db8::1"},
+		{ip: "2001:0db8:85a3:0000:0000:8a2e:0370:7334", policy: UnmapIPv4, expected: "2001:db8:85a3::8a2e:370:7334"},
+		{ip: "::ffff:1.2.3.4", policy: UnmapIPv4, expected: "1.2.3.4"},
+		{ip: "::FFFF:1.2.3.4", policy: KeepMappedIPv4, expected: "::ffff:1.2.3.4"},
+		{ip: "1.2.3.4", policy: KeepMappedIPv4, expected: "1.2.3.4"},
+	} {
+		ip, err := CanonicalIPAddress(tc.ip, tc.policy)
+		require.NoError(t, err, tc.ip)
+		require.Equal(t, tc.expected, ip, tc.ip)
+	}
+}
+
+func TestCanonicalIPAddress_invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, ip := range []string{"", "200.106.141", "2001:db8::g", "fe80::1%eth0"} {
+		_, err := CanonicalIPAddress(ip, UnmapIPv4)
+		require.Error(t, err, ip)
+	}
+}
+
+func TestDecodeMappedIPv4Policy(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, UnmapIPv4, DecodeMappedIPv4Policy())
+	require.Equal(t, UnmapIPv4, DecodeMappedIPv4Policy(WithFractionPolicy(FractionRound)))
+	require.Equal(t, KeepMappedIPv4, DecodeMappedIPv4Policy(WithMappedIPv4Policy(KeepMappedIPv4)))
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"strconv"
+)
+
+// FractionPolicy defines how a fractional mystery value is handled.
+type FractionPolicy string
+
+const (
+	// FractionReject discards the geolocation data with a fractional mystery value.
+	FractionReject FractionPolicy = "reject"
+	// FractionRound rounds the fractional mystery value half away from zero.
+	FractionRound FractionPolicy = "round"
+)
+
+// parseMysteryValue parses the mystery value as a 64-bit integer, the range of the storage.
+//
+// Integral values written as decimals, i.e. 42.0 or 4.2e1, are accepted as long as they are exact.
+func parseMysteryValue(v string, policy FractionPolicy) (int64, error) {
+	i, err := strconv.ParseInt(v, 10, 64)
+	if err == nil {
+		return i, nil
+	}
+
+	if errors.Is(err, strconv.ErrRange) {
+		return 0, NewValidationError(ColMysteryValue, CodeMysteryValueOutOfRange, v)
+	}
+
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, NewValidationError(ColMysteryValue, CodeInvalidMysteryValue, v)
+	}
+
+	if f != math.Trunc(f) {
+		if policy != FractionRound {
+			return 0, NewValidationError(ColMysteryValue, CodeFractionalMysteryValue, v)
+		}
+
+		f = math.Round(f)
+	}
+
+	// Past 2^53 float64 no longer tells the consecutive integers apart, the value may not be the one written.
+	if math.Abs(f) >= 1<<53 {
+		return 0, NewValidationError(ColMysteryValue, CodeMysteryValueOutOfRange, v)
+	}
+
+	return int64(f), nil
+}
@@ -0,0 +1,61 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseMysteryValue(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		value  string
+		policy FractionPolicy
+		want   int64
+		code   ErrorCode
+	}{
+		{value: "7823011346", policy: FractionReject, want: 7823011346},
+		{value: "-42", policy: FractionReject, want: -42},
+		{value: "9223372036854775807", policy: FractionReject, want: 9223372036854775807},
+		{value: "42.0", policy: FractionReject, want: 42},
+		{value: "4.2e1", policy: FractionReject, want: 42},
+		{value: "42.5", policy: FractionReject, code: CodeFractionalMysteryValue},
+		{value: "42.5", policy: FractionRound, want: 43},
+		{value: "-42.5", policy: FractionRound, want: -43},
+		{value: "42.4", policy: FractionRound, want: 42},
+		{value: "9223372036854775808", policy: FractionReject, code: CodeMysteryValueOutOfRange},
+		{value: "1e19", policy: FractionRound, code: CodeMysteryValueOutOfRange},
+		{value: "9007199254740993.0", policy: FractionReject, code: CodeMysteryValueOutOfRange},
+		{value: "NaN", policy: FractionRound, code: CodeInvalidMysteryValue},
+		{value: "", policy: FractionReject, code: CodeInvalidMysteryValue},
+		{value: "mystery", policy: FractionReject, code: CodeInvalidMysteryValue},
+	} {
+		got, err := parseMysteryValue(tc.value, tc.policy)
+
+		if tc.code != "" {
+			var verr *ValidationError
+
+			require.ErrorAs(t, err, &verr, tc.value)
+			require.Equal(t, tc.code, verr.Code, tc.value)
+
+			continue
+		}
+
+		require.NoError(t, err, tc.value)
+		require.Equal(t, tc.want, got, tc.value)
+	}
+}
+
+func TestDecodeGeolocation_fraction(t *testing.T) {
+	t.Parallel()
+
+	data := []string{"200.106.141.15", "SI", "Slovenia", "Ljubljana", "46.0569", "14.5058", "7823011346.6"}
+
+	_, err := DecodeGeolocation(data)
+	require.EqualError(t, err, "fractional mystery value")
+
+	geo, err := DecodeGeolocation(data, WithFractionPolicy(FractionRound))
+	require.NoError(t, err)
+	require.Equal(t, int64(7823011347), geo.MysteryValue)
+}
@@ -0,0 +1,270 @@
+package model
+
+import (
+	"context"
+	"net/netip"
+	"time"
+	// The time zones are validated regardless of the database of the host.
+	_ "time/tzdata"
+
+	"github.com/bool64/ctxd"
+)
+
+// Severity defines what happens to the geolocation data violating a rule.
+type Severity string
+
+const (
+	// SeverityReject discards the geolocation data.
+	SeverityReject Severity = "reject"
+	// SeverityWarn accepts the geolocation data, the violation is only counted.
+	SeverityWarn Severity = "warn"
+	// SeverityOff disables the rule.
+	SeverityOff Severity = "off"
+)
+
+// Built-in rule names.
+const (
+	RuleIPAddress          = "ip_address"
+	RuleCountryCode        = "country_code"
+	RuleCountry            = "country"
+	RuleCity               = "city"
+	RuleLatitude           = "latitude"
+	RuleLongitude          = "longitude"
+	RuleTimeZone           = "time_zone"
+	RuleNonZeroCoordinates = "non_zero_coordinates"
+	RulePublicIP           = "public_ip"
+	RuleBlocklist          = "blocklist"
+)
+
+// RuleConfig configures a rule of a RuleSet.
+type RuleConfig struct {
+	Name     string   `json:"name" yaml:"name"`
+	Severity Severity `json:"severity" yaml:"severity"`
+	// CIDRs are the ranges rejected by the blocklist rule.
+	CIDRs []string `json:"cidrs,omitempty" yaml:"cidrs,omitempty"`
+}
+
+// Rule is a named check of the geolocation data.
+type Rule struct {
+	Name     string
+	Severity Severity
+
+	check func(g Geolocation) error
+}
+
+// Violation is a rule the geolocation data does not comply with.
+type Violation struct {
+	Rule     string
+	Severity Severity
+	Err      error
+}
+
+// RuleSet is an ordered set of rules validating the geolocation data.
+type RuleSet struct {
+	rules []Rule
+}
+
+// builtinRules returns the built-in rules along with their default severity, in evaluation order.
+//
+// The rules rejecting by default make up IsValid.
+func builtinRules() []Rule {
+	return []Rule{
+		{Name: RuleIPAddress, Severity: SeverityReject, check: checkIPAddress},
+		{Name: RuleCountryCode, Severity: SeverityReject, check: checkCountryCode},
+		{Name: RuleCountry, Severity: SeverityReject, check: checkCountry},
+		{Name: RuleCity, Severity: SeverityReject, check: checkCity},
+		{Name: RuleLatitude, Severity: SeverityReject, check: checkLatitude},
+		{Name: RuleLongitude, Severity: SeverityReject, check: checkLongitude},
+		{Name: RuleTimeZone, Severity: SeverityReject, check: checkTimeZone},
+		{Name: RuleNonZeroCoordinates, Severity: SeverityOff, check: checkNonZeroCoordinates},
+		{Name: RulePublicIP, Severity: SeverityOff, check: checkPublicIP},
+		{Name: RuleBlocklist, Severity: SeverityOff, check: func(Geolocation) error { return nil }},
+	}
+}
+
+// defaultRuleSet is the rule set of IsValid.
+var defaultRuleSet = DefaultRuleSet()
+
+// DefaultRuleSet returns the rule set with the built-in rules at their default severity.
+func DefaultRuleSet() *RuleSet {
+	return &RuleSet{rules: builtinRules()}
+}
+
+// NewRuleSet returns the default rule set with the severity of the rules overridden by configs.
+//
+// Returns an error when a config refers to an unknown rule or severity, or downgrades the ip_address rule: the
+// geolocation data is stored and looked up by IP address, it can not be accepted without a valid one.
+func NewRuleSet(configs ...RuleConfig) (*RuleSet, error) {
+	rs := DefaultRuleSet()
+
+	for _, cfg := range configs {
+		if cfg.Severity != SeverityReject && cfg.Severity != SeverityWarn && cfg.Severity != SeverityOff {
+			return nil, ctxd.NewError(context.Background(), "unknown rule severity", "rule", cfg.Name, "severity", cfg.Severity)
+		}
+
+		if cfg.Name == RuleIPAddress && cfg.Severity != SeverityReject {
+			return nil, ctxd.NewError(context.Background(), "rule always rejecting", "rule", cfg.Name, "severity", cfg.Severity)
+		}
+
+		found := false
+
+		for i := range rs.rules {
+			if rs.rules[i].Name != cfg.Name {
+				continue
+			}
+
+			rs.rules[i].Severity = cfg.Severity
+			found = true
+
+			if cfg.Name == RuleBlocklist {
+				check, err := blocklist(cfg.CIDRs)
+				if err != nil {
+					return nil, err
+				}
+
+				rs.rules[i].check = check
+			}
+
+			break
+		}
+
+		if !found {
+			return nil, ctxd.NewError(context.Background(), "unknown rule", "rule", cfg.Name)
+		}
+	}
+
+	return rs, nil
+}
+
+// Rules returns the rules of the set, in evaluation order.
+func (rs *RuleSet) Rules() []Rule {
+	return append([]Rule(nil), rs.rules...)
+}
+
+// Validate evaluates the rules against the geolocation data.
+//
+// Returns the violations found, in evaluation order. The evaluation stops at the first violation with
+// SeverityReject, which is then the last one returned.
+func (rs *RuleSet) Validate(g Geolocation) []Violation {
+	var violations []Violation
+
+	for _, rule := range rs.rules {
+		if rule.Severity == SeverityOff {
+			continue
+		}
+
+		err := rule.check(g)
+		if err == nil {
+			continue
+		}
+
+		violations = append(violations, Violation{Rule: rule.Name, Severity: rule.Severity, Err: err})
+
+		if rule.Severity == SeverityReject {
+			break
+		}
+	}
+
+	return violations
+}
+
+// Check returns the error of the first rule rejecting the geolocation data, nil otherwise.
+func (rs *RuleSet) Check(g Geolocation) error {
+	violations := rs.Validate(g)
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if v := violations[len(violations)-1]; v.Severity == SeverityReject {
+		return v.Err
+	}
+
+	return nil
+}
+
+func checkIPAddress(g Geolocation) error {
+	if g.IPAddress == "" {
+		return NewValidationError("ip_address", CodeMissingIPAddress, nil)
+	}
+
+	addr, err := netip.ParseAddr(g.IPAddress)
+	if err != nil {
+		return NewValidationError("ip_address", CodeInvalidIPAddress, g.IPAddress)
+	}
+
+	if addr.Zone() != "" {
+		return NewValidationError("ip_address", CodeInvalidIPAddress, g.IPAddress)
+	}
+
+	return nil
+}
+
+func checkCountryCode(g Geolocation) error {
+	if g.CountryCode == "" {
+		return NewValidationError("country_code", CodeMissingCountryCode, nil)
+	}
+
+	if len(g.CountryCode) != 2 {
+		return NewValidationError("country_code", CodeInvalidCountryCodeLength, g.CountryCode)
+	}
+
+	if !IsCountryCode(g.CountryCode) {
+		return NewValidationError("country_code", CodeUnknownCountryCode, g.CountryCode)
+	}
+
+	return nil
+}
+
+func checkCountry(g Geolocation) error {
+	if g.Country == "" {
+		return NewValidationError("country", CodeMissingCountry, nil)
+	}
+
+	return nil
+}
+
+func checkCity(g Geolocation) error {
+	if g.City == "" {
+		return NewValidationError("city", CodeMissingCity, nil)
+	}
+
+	return nil
+}
+
+func checkLatitude(g Geolocation) error {
+	if g.Latitude < -90 || g.Latitude > 90 {
+		return NewValidationError("latitude", CodeInvalidLatitude, g.Latitude)
+	}
+
+	return nil
+}
+
+func checkLongitude(g Geolocation) error {
+	if g.Longitude < -180 || g.Longitude > 180 {
+		return NewValidationError("longitude", CodeInvalidLongitude, g.Longitude)
+	}
+
+	return nil
+}
+
+// checkTimeZone checks the time zone, when known, is an IANA time zone.
+func checkTimeZone(g Geolocation) error {
+	if g.TimeZone == "" {
+		return nil
+	}
+
+	if _, err := time.LoadLocation(g.TimeZone); err != nil || g.TimeZone == "Local" {
+		return NewValidationError(ColTimeZone, CodeInvalidTimeZone, g.TimeZone)
+	}
+
+	return nil
+}
+
+func checkNonZeroCoordinates(g Geolocation) error {
+	if g.Latitude == 0 && g.Longitude == 0 {
+		return NewValidationError("", CodeZeroCoordinates, nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRuleSet_Validate(t *testing.T) {
+	t.Parallel()
+
+	rs, err := NewRuleSet(
+		RuleConfig{Name: RuleCity, Severity: SeverityWarn},
+		RuleConfig{Name: RuleNonZeroCoordinates, Severity: SeverityReject},
+	)
+	require.NoError(t, err)
+
+	geo := Geolocation{
+		IPAddress:   "200.106.141.15",
+		CountryCode: "SI",
+		Country:     "Nepal",
+		Latitude:    -84.87503094689836,
+		Longitude:   7.206435933364332,
+	}
+
+	violations := rs.Validate(geo)
+	require.Len(t, violations, 1)
+	require.Equal(t, RuleCity, violations[0].Rule)
+	require.Equal(t, SeverityWarn, violations[0].Severity)
+	require.NoError(t, rs.Check(geo))
+
+	geo.Latitude, geo.Longitude = 0, 0
+
+	violations = rs.Validate(geo)
+	require.Len(t, violations, 2)
+	require.Equal(t, RuleNonZeroCoordinates, violations[1].Rule)
+	require.EqualError(t, rs.Check(geo), "zero coordinates")
+
+	// The default rule set rejects the missing city and accepts zero coordinates.
+	require.EqualError(t, geo.IsValid(), "missing city")
+
+	geo.City = "DuBuquemouth"
+	require.NoError(t, geo.IsValid())
+}
+
+func TestNewRuleSet_invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewRuleSet(RuleConfig{Name: "unknown", Severity: SeverityWarn})
+	require.EqualError(t, err, "unknown rule")
+
+	_, err = NewRuleSet(RuleConfig{Name: RuleCity, Severity: "fatal"})
+	require.EqualError(t, err, "unknown rule severity")
+
+	_, err = NewRuleSet(RuleConfig{Name: RuleIPAddress, Severity: SeverityWarn})
+	require.EqualError(t, err, "rule always rejecting")
+
+	_, err = NewRuleSet(RuleConfig{Name: RuleIPAddress, Severity: SeverityOff})
+	require.EqualError(t, err, "rule always rejecting")
+
+	_, err = NewRuleSet(RuleConfig{Name: RuleIPAddress, Severity: SeverityReject})
+	require.NoError(t, err)
+}
@@ -0,0 +1,131 @@
+package model
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/bool64/ctxd"
+)
+
+// IPClass classifies the IP addresses which make no sense to geolocate.
+type IPClass string
+
+// IP classes.
+const (
+	IPUnspecified   IPClass = "unspecified"
+	IPLoopback      IPClass = "loopback"
+	IPPrivate       IPClass = "private"
+	IPLinkLocal     IPClass = "link-local"
+	IPMulticast     IPClass = "multicast"
+	IPDocumentation IPClass = "documentation"
+	IPReserved      IPClass = "reserved"
+)
+
+// ipClassCodes are the codes of the ValidationError rejecting the IP addresses of each class.
+var ipClassCodes = map[IPClass]ErrorCode{
+	IPUnspecified:   CodeUnspecifiedIPAddress,
+	IPLoopback:      CodeLoopbackIPAddress,
+	IPPrivate:       CodePrivateIPAddress,
+	IPLinkLocal:     CodeLinkLocalIPAddress,
+	IPMulticast:     CodeMulticastIPAddress,
+	IPDocumentation: CodeDocumentationIPAddress,
+	IPReserved:      CodeReservedIPAddress,
+}
+
+// specialPrefixes are the special-purpose ranges (RFC 6890 and updates) not covered by the netip.Addr predicates.
+var specialPrefixes = []struct {
+	prefix netip.Prefix
+	class  IPClass
+}{
+	// IPv4.
+	{netip.MustParsePrefix("0.0.0.0/8"), IPReserved},         // This network.
+	{netip.MustParsePrefix("100.64.0.0/10"), IPPrivate},      // Shared address space (CGNAT).
+	{netip.MustParsePrefix("192.0.0.0/24"), IPReserved},      // IETF protocol assignments.
+	{netip.MustParsePrefix("192.0.2.0/24"), IPDocumentation}, // TEST-NET-1.
+	{netip.MustParsePrefix("198.18.0.0/15"), IPReserved},     // Benchmarking.
+	{netip.MustParsePrefix("198.51.100.0/24"), IPDocumentation},
+	{netip.MustParsePrefix("203.0.113.0/24"), IPDocumentation},
+	{netip.MustParsePrefix("240.0.0.0/4"), IPReserved}, // Reserved for future use, including the broadcast.
+	// IPv6.
+	{netip.MustParsePrefix("64:ff9b:1::/48"), IPPrivate}, // Local-use IPv4/IPv6 translation.
+	{netip.MustParsePrefix("100::/64"), IPReserved},      // Discard-only.
+	{netip.MustParsePrefix("2001::/23"), IPReserved},     // IETF protocol assignments.
+	{netip.MustParsePrefix("2001:db8::/32"), IPDocumentation},
+	{netip.MustParsePrefix("3fff::/20"), IPDocumentation},
+}
+
+// ClassifyIP returns the class of the IP address when it is in a private, reserved or otherwise special-purpose range,
+// IPv4 or IPv6. Returns false for the addresses which can be geolocated.
+//
+// IPv4-mapped IPv6 addresses are classified as the IPv4 address.
+func ClassifyIP(addr netip.Addr) (IPClass, bool) {
+	addr = addr.Unmap()
+
+	switch {
+	case addr.IsUnspecified():
+		return IPUnspecified, true
+	case addr.IsLoopback():
+		return IPLoopback, true
+	case addr.IsPrivate():
+		return IPPrivate, true
+	case addr.IsLinkLocalUnicast():
+		return IPLinkLocal, true
+	case addr.IsMulticast():
+		return IPMulticast, true
+	}
+
+	for _, sp := range specialPrefixes {
+		if sp.prefix.Contains(addr) {
+			return sp.class, true
+		}
+	}
+
+	return "", false
+}
+
+func checkPublicIP(g Geolocation) error {
+	addr, err := netip.ParseAddr(g.IPAddress)
+	if err != nil {
+		// Left to the ip_address rule.
+		return nil
+	}
+
+	if class, ok := ClassifyIP(addr); ok {
+		return NewValidationError("ip_address", ipClassCodes[class], g.IPAddress)
+	}
+
+	return nil
+}
+
+// blocklist returns the check rejecting the IP addresses within the given CIDRs.
+func blocklist(cidrs []string) (func(g Geolocation) error, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, ctxd.WrapError(context.Background(), err, "invalid blocklist cidr", "cidr", cidr)
+		}
+
+		prefixes = append(prefixes, prefix.Masked())
+	}
+
+	return func(g Geolocation) error {
+		addr, err := netip.ParseAddr(g.IPAddress)
+		if err != nil {
+			// Left to the ip_address rule.
+			return nil
+		}
+
+		// Match both the IPv4 and the IPv4-mapped IPv6 form.
+		unmapped := addr.Unmap()
+
+		for _, prefix := range prefixes {
+			if prefix.Contains(addr) || prefix.Contains(unmapped) {
+				return NewValidationError("ip_address", CodeBlocklistedIPAddress, g.IPAddress)
+			}
+		}
+
+		return nil
+	}, nil
+}
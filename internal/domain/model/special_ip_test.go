@@ -0,0 +1,81 @@
+package model
+
+import (
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyIP(t *testing.T) {
+	t.Parallel()
+
+	for ip, expected := range map[string]IPClass{
+		"0.0.0.0":            IPUnspecified,
+		"::":                 IPUnspecified,
+		"127.0.0.1":          IPLoopback,
+		"::1":                IPLoopback,
+		"10.1.2.3":           IPPrivate,
+		"172.16.0.1":         IPPrivate,
+		"192.168.1.1":        IPPrivate,
+		"100.64.0.1":         IPPrivate,
+		"fd00::1":            IPPrivate,
+		"::ffff:192.168.1.1": IPPrivate,
+		"169.254.1.1":        IPLinkLocal,
+		"fe80::1":            IPLinkLocal,
+		"224.0.0.1":          IPMulticast,
+		"ff02::1":            IPMulticast,
+		"192.0.2.1":          IPDocumentation,
+		"198.51.100.1":       IPDocumentation,
+		"203.0.113.1":        IPDocumentation,
+		"2001:db8::1":        IPDocumentation,
+		"0.1.2.3":            IPReserved,
+		"198.18.0.1":         IPReserved,
+		"240.0.0.1":          IPReserved,
+		"255.255.255.255":    IPReserved,
+		"100::1":             IPReserved,
+	} {
+		class, ok := ClassifyIP(netip.MustParseAddr(ip))
+		require.True(t, ok, ip)
+		require.Equal(t, expected, class, ip)
+	}
+
+	for _, ip := range []string{"200.106.141.15", "160.103.7.140", "2a00:1450:4001::200e", "::ffff:70.95.73.73"} {
+		_, ok := ClassifyIP(netip.MustParseAddr(ip))
+		require.False(t, ok, ip)
+	}
+}
+
+func TestRuleSet_public_ip_and_blocklist(t *testing.T) {
+	t.Parallel()
+
+	rs, err := NewRuleSet(
+		RuleConfig{Name: RulePublicIP, Severity: SeverityReject},
+		RuleConfig{Name: RuleBlocklist, Severity: SeverityReject, CIDRs: []string{"200.106.0.0/16", "2a00:1450::/32"}},
+	)
+	require.NoError(t, err)
+
+	geo := Geolocation{
+		CountryCode: "SI",
+		Country:     "Nepal",
+		City:        "DuBuquemouth",
+	}
+
+	for ip, reason := range map[string]string{
+		"192.168.1.1":          "private ip address",
+		"127.0.0.1":            "loopback ip address",
+		"2001:db8::1":          "documentation ip address",
+		"200.106.141.15":       "blocklisted ip address",
+		"::ffff:200.106.141.5": "blocklisted ip address",
+		"2a00:1450:4001::200e": "blocklisted ip address",
+	} {
+		geo.IPAddress = ip
+		require.EqualError(t, rs.Check(geo), reason, ip)
+	}
+
+	geo.IPAddress = "160.103.7.140"
+	require.NoError(t, rs.Check(geo))
+
+	_, err = NewRuleSet(RuleConfig{Name: RuleBlocklist, Severity: SeverityReject, CIDRs: []string{"10.0.0.0"}})
+	require.ErrorContains(t, err, "invalid blocklist cidr")
+}
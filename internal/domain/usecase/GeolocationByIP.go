@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	"github.com/bool64/ctxd"
 	"github.com/dohernandez/vio/internal/domain/model"
@@ -11,28 +12,68 @@ import (
 
 // GeolocationByIPFinder is the interface that provides the ability to find geolocation by IP.
 //
+// The geolocation is looked up in the datasets in order, the one of the first dataset having it is returned. The
+// finder chooses the dataset when none is given.
+//
 // Returns ErrGeolocationNotFound if the geolocation is not found.
 type GeolocationByIPFinder interface {
-	FindGeolocationByIP(ctx context.Context, ip string) (model.Geolocation, error)
+	FindGeolocationByIP(ctx context.Context, ip string, datasets []string) (model.Geolocation, error)
+	// FindGeolocationByIPAsOf finds the geolocation by IP as it was at the given time.
+	FindGeolocationByIPAsOf(ctx context.Context, ip string, asOf time.Time, datasets []string) (model.Geolocation, error)
 }
 
 // GeolocationByIPExposer exposes the geolocation by IP.
 type GeolocationByIPExposer struct {
 	finder GeolocationByIPFinder
+	// datasets is the priority of the datasets looked up when the request does not choose.
+	datasets []string
 }
 
 // NewGeolocationByIPExposer creates a new GeolocationByIPExposer.
-func NewGeolocationByIPExposer(finder GeolocationByIPFinder) *GeolocationByIPExposer {
+//
+// The datasets are looked up in the given order of priority unless the request chooses others, i.e. provider A
+// first, then B.
+func NewGeolocationByIPExposer(finder GeolocationByIPFinder, datasets ...string) *GeolocationByIPExposer {
 	return &GeolocationByIPExposer{
-		finder: finder,
+		finder:   finder,
+		datasets: datasets,
+	}
+}
+
+// lookupDatasets returns the datasets to look up, the default priority when none is given.
+func (e *GeolocationByIPExposer) lookupDatasets(datasets []string) []string {
+	if len(datasets) == 0 {
+		return e.datasets
 	}
+
+	return datasets
 }
 
-// ExposeGeolocationByIP exposes the geolocation by IP.
+// ExposeGeolocationByIP exposes the geolocation by IP from the first of the datasets having it.
+//
+// The dataset of the geolocation tells which dataset answered.
 //
 // Returns ErrGeolocationNotFound if the geolocation is not found.
-func (e *GeolocationByIPExposer) ExposeGeolocationByIP(ctx context.Context, ip string) (model.Geolocation, error) {
-	geo, err := e.finder.FindGeolocationByIP(ctx, ip)
+func (e *GeolocationByIPExposer) ExposeGeolocationByIP(ctx context.Context, ip string, datasets ...string) (model.Geolocation, error) {
+	geo, err := e.finder.FindGeolocationByIP(ctx, ip, e.lookupDatasets(datasets))
+	if err != nil {
+		return model.Geolocation{}, ctxd.WrapError(ctx, model.ErrGeolocationNotFound, err.Error())
+	}
+
+	return geo, nil
+}
+
+// ExposeGeolocationByIPAsOf exposes the geolocation by IP as it was at the given time, from the first of the datasets
+// having it.
+//
+// Returns ErrGeolocationNotFound if the IP was not geolocated at that time.
+func (e *GeolocationByIPExposer) ExposeGeolocationByIPAsOf(
+	ctx context.Context,
+	ip string,
+	asOf time.Time,
+	datasets ...string,
+) (model.Geolocation, error) {
+	geo, err := e.finder.FindGeolocationByIPAsOf(ctx, ip, asOf, e.lookupDatasets(datasets))
 	if err != nil {
 		return model.Geolocation{}, ctxd.WrapError(ctx, model.ErrGeolocationNotFound, err.Error())
 	}
@@ -2,7 +2,9 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/dohernandez/vio/internal/domain/model"
 	"github.com/dohernandez/vio/internal/domain/usecase/mocks"
@@ -16,7 +18,7 @@ func TestGeolocationByIPExposer_ExposeGeolocationByIP_success(t *testing.T) {
 	ip := "200.106.141.15"
 
 	finder := mocks.NewGeolocationByIPFinder(t)
-	finder.EXPECT().FindGeolocationByIP(mock.Anything, ip).Return(model.Geolocation{IPAddress: ip}, nil)
+	finder.EXPECT().FindGeolocationByIP(mock.Anything, ip, []string(nil)).Return(model.Geolocation{IPAddress: ip}, nil)
 
 	exposer := NewGeolocationByIPExposer(finder)
 
@@ -24,3 +26,48 @@ func TestGeolocationByIPExposer_ExposeGeolocationByIP_success(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, model.Geolocation{IPAddress: ip}, geo)
 }
+
+func TestGeolocationByIPExposer_ExposeGeolocationByIPAsOf(t *testing.T) {
+	t.Parallel()
+
+	ip := "200.106.141.15"
+	asOf := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	finder := mocks.NewGeolocationByIPFinder(t)
+	finder.EXPECT().FindGeolocationByIPAsOf(mock.Anything, ip, asOf, []string(nil)).Return(model.Geolocation{IPAddress: ip}, nil).Once()
+	finder.EXPECT().FindGeolocationByIPAsOf(mock.Anything, ip, asOf.AddDate(-2, 0, 0), []string(nil)).
+		Return(model.Geolocation{}, errors.New("not found")).Once()
+
+	exposer := NewGeolocationByIPExposer(finder)
+
+	geo, err := exposer.ExposeGeolocationByIPAsOf(context.Background(), ip, asOf)
+	require.NoError(t, err)
+	require.Equal(t, model.Geolocation{IPAddress: ip}, geo)
+
+	_, err = exposer.ExposeGeolocationByIPAsOf(context.Background(), ip, asOf.AddDate(-2, 0, 0))
+	require.ErrorIs(t, err, model.ErrGeolocationNotFound)
+}
+
+func TestGeolocationByIPExposer_ExposeGeolocationByIP_datasets(t *testing.T) {
+	t.Parallel()
+
+	ip := "200.106.141.15"
+
+	finder := mocks.NewGeolocationByIPFinder(t)
+	finder.EXPECT().FindGeolocationByIP(mock.Anything, ip, []string{"maxmind", "ip2location"}).
+		Return(model.Geolocation{IPAddress: ip, Dataset: "ip2location"}, nil).Once()
+	finder.EXPECT().FindGeolocationByIP(mock.Anything, ip, []string{"ip2location"}).
+		Return(model.Geolocation{IPAddress: ip, Dataset: "ip2location"}, nil).Once()
+
+	exposer := NewGeolocationByIPExposer(finder, "maxmind", "ip2location")
+
+	// The default priority.
+	geo, err := exposer.ExposeGeolocationByIP(context.Background(), ip)
+	require.NoError(t, err)
+	require.Equal(t, "ip2location", geo.Dataset)
+
+	// The datasets of the request.
+	geo, err = exposer.ExposeGeolocationByIP(context.Background(), ip, "ip2location")
+	require.NoError(t, err)
+	require.Equal(t, "ip2location", geo.Dataset)
+}
@@ -0,0 +1,69 @@
+package usecase
+
+import "time"
+
+const (
+	// defaultBatchSize is the default number of geolocation data saved at once.
+	defaultBatchSize = 500
+	// defaultSaverWorkers is the default number of workers saving geolocation data in parallel.
+	defaultSaverWorkers = 15
+
+	// minAutoTuneBatchSize is the lower bound of the batch size when auto-tuning, the maximum batch size being the
+	// upper one.
+	minAutoTuneBatchSize = 50
+	// defaultMaxBatchSize is the maximum number of geolocation data saved at once by a storage not limiting it, see
+	// GeolocationDataBatchLimiter.
+	defaultMaxBatchSize = 10000
+)
+
+// GeolocationDataBatchLimiter is implemented by the GeolocationDataStorage limiting the number of geolocation data saved
+// at once, i.e. by the bind parameters of a statement.
+type GeolocationDataBatchLimiter interface {
+	MaxBatchSize() int
+}
+
+// maxBatchSize returns the maximum number of geolocation data the storage saves at once.
+func maxBatchSize(storage GeolocationDataStorage) int {
+	if l, ok := storage.(GeolocationDataBatchLimiter); ok {
+		return l.MaxBatchSize()
+	}
+
+	return defaultMaxBatchSize
+}
+
+// batchSizer decides the size of the next batch to save.
+//
+// With a zero target latency the size is fixed. Otherwise, the size is adjusted after each save, doubling it when the
+// observed insert latency is below half of the target and halving it when the latency is above the target.
+type batchSizer struct {
+	current int
+	maximum int
+	target  time.Duration
+}
+
+func newBatchSizer(size, maximum int, target time.Duration) *batchSizer {
+	return &batchSizer{
+		current: size,
+		maximum: maximum,
+		target:  target,
+	}
+}
+
+func (b *batchSizer) size() int {
+	return b.current
+}
+
+// observe records the latency of saving a batch of n items.
+func (b *batchSizer) observe(n int, latency time.Duration) {
+	// A partial batch says nothing about the latency of a full one.
+	if b.target == 0 || n < b.current {
+		return
+	}
+
+	switch {
+	case latency < b.target/2:
+		b.current = min(b.current*2, b.maximum)
+	case latency > b.target:
+		b.current = max(b.current/2, minAutoTuneBatchSize)
+	}
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchSizer_fixed(t *testing.T) {
+	t.Parallel()
+
+	sizer := newBatchSizer(500, 4000, 0)
+
+	sizer.observe(500, time.Hour)
+	require.Equal(t, 500, sizer.size())
+
+	sizer.observe(500, time.Nanosecond)
+	require.Equal(t, 500, sizer.size())
+}
+
+func TestBatchSizer_autotune(t *testing.T) {
+	t.Parallel()
+
+	sizer := newBatchSizer(500, 4000, 100*time.Millisecond)
+
+	// Fast inserts grow the batch size up to the maximum.
+	sizer.observe(500, 10*time.Millisecond)
+	require.Equal(t, 1000, sizer.size())
+
+	for range 10 {
+		sizer.observe(sizer.size(), 10*time.Millisecond)
+	}
+
+	require.Equal(t, 4000, sizer.size())
+
+	// Latency within the target keeps the batch size.
+	sizer.observe(sizer.size(), 80*time.Millisecond)
+	require.Equal(t, 4000, sizer.size())
+
+	// Partial batches are ignored.
+	sizer.observe(10, time.Second)
+	require.Equal(t, 4000, sizer.size())
+
+	// Slow inserts shrink the batch size down to the minimum.
+	sizer.observe(sizer.size(), time.Second)
+	require.Equal(t, 2000, sizer.size())
+
+	for range 10 {
+		sizer.observe(sizer.size(), time.Second)
+	}
+
+	require.Equal(t, minAutoTuneBatchSize, sizer.size())
+}
+
+// batchLimitedStorage is a GeolocationDataStorage saving up to max geolocation data at once.
+type batchLimitedStorage struct {
+	GeolocationDataStorage
+
+	max int
+}
+
+func (s batchLimitedStorage) MaxBatchSize() int {
+	return s.max
+}
+
+func TestWithBatchSize(t *testing.T) {
+	t.Parallel()
+
+	p := NewParseGeolocationData(nil, nil, WithBatchSize(100))
+	require.Equal(t, 100, p.batchSize)
+	require.Equal(t, defaultMaxBatchSize, p.maxBatchSize)
+
+	p = NewParseGeolocationData(nil, nil, WithBatchSize(defaultMaxBatchSize+1))
+	require.Equal(t, defaultMaxBatchSize, p.batchSize)
+
+	// Larger batches would not fit in a single insert of the storage.
+	p = NewParseGeolocationData(batchLimitedStorage{max: 1820}, nil, WithBatchSize(2000))
+	require.Equal(t, 1820, p.batchSize)
+	require.Equal(t, 1820, p.maxBatchSize)
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bool64/ctxd"
+)
+
+//go:generate mockery --name=GeolocationDataIndexer --outpkg=mocks --output=mocks --filename=geolocation_data_indexer.go --with-expecter
+
+// GeolocationDataIndexer is the interface that provides the ability to drop the indexes of the stored geolocation data
+// around a bulk load.
+type GeolocationDataIndexer interface {
+	// DropGeolocationIndexes drops the indexes, keeping their definition to restore them.
+	DropGeolocationIndexes(ctx context.Context) error
+	// RestoreGeolocationIndexes recreates the indexes dropped.
+	RestoreGeolocationIndexes(ctx context.Context) error
+}
+
+// ErrBulkLoadMode is returned when the bulk load is combined with the incremental or sync mode, which look up the
+// stored geolocation data by IP as they go.
+var ErrBulkLoadMode = errors.New("bulk load requires the insert mode")
+
+// WithBulkLoad enables the bulk load mode.
+//
+// The indexes of the stored geolocation data are dropped before inserting and recreated once done, whether the
+// processing succeeds or not, so that they are built once instead of being updated row by row. Lookups are slow
+// meanwhile. Bulk load requires the insert mode.
+func WithBulkLoad(indexer GeolocationDataIndexer) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.indexer = indexer
+	}
+}
+
+// bulkLoad loads the geolocation data from the given reader with the indexes dropped.
+func (p *GeolocationDataProcessor) bulkLoad(ctx context.Context, reader GeolocationDataReader, inParallel uint) (Report, error) {
+	if p.merger != nil {
+		return Report{}, ErrBulkLoadMode
+	}
+
+	if err := p.indexer.DropGeolocationIndexes(ctx); err != nil {
+		return Report{}, err
+	}
+
+	p.logger.Important(ctx, "geolocation indexes dropped for bulk load")
+
+	res, err := p.load(ctx, reader, inParallel)
+
+	// Indexes are restored even when the processing is canceled.
+	if rerr := p.indexer.RestoreGeolocationIndexes(context.WithoutCancel(ctx)); rerr != nil {
+		p.logger.Error(ctx, "failed to restore geolocation indexes", "error", rerr)
+
+		return Report{}, errors.Join(err, ctxd.WrapError(ctx, rerr, "restoring geolocation indexes"))
+	}
+
+	p.logger.Important(ctx, "geolocation indexes restored")
+
+	return res, err
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/domain/usecase/mocks"
+	"github.com/dohernandez/vio/internal/platform/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleReader returns the reader of the sample data.
+func sampleReader(t *testing.T) *mocks.GeolocationDataReader {
+	t.Helper()
+
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	return reader
+}
+
+func TestGeolocationDataProcessor_Process_bulk_load(t *testing.T) {
+	t.Parallel()
+
+	var calls []string
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, _ []*model.Geolocation) {
+			calls = append(calls, "save")
+		}).
+		Return(nil)
+
+	indexer := mocks.NewGeolocationDataIndexer(t)
+	indexer.EXPECT().DropGeolocationIndexes(mock.Anything).
+		Run(func(_ context.Context) { calls = append(calls, "drop") }).
+		Return(nil).Once()
+	indexer.EXPECT().RestoreGeolocationIndexes(mock.Anything).
+		Run(func(_ context.Context) { calls = append(calls, "restore") }).
+		Return(nil).Once()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithBulkLoad(indexer),
+	)
+
+	report, err := processor.Process(context.Background(), sampleReader(t), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, []string{"drop", "save", "restore"}, calls)
+}
+
+func TestGeolocationDataProcessor_Process_bulk_load_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	indexer := mocks.NewGeolocationDataIndexer(t)
+	indexer.EXPECT().DropGeolocationIndexes(mock.Anything).
+		Run(func(_ context.Context) { cancel() }).
+		Return(nil).Once()
+	indexer.EXPECT().RestoreGeolocationIndexes(mock.Anything).
+		Run(func(ctx context.Context) {
+			// Indexes are restored even though the processing is canceled.
+			assert.NoError(t, ctx.Err())
+		}).
+		Return(errors.New("connection refused")).Once()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{}, WithBulkLoad(indexer))
+
+	_, err := processor.Process(ctx, sampleReader(t), 1)
+	require.ErrorContains(t, err, "restoring geolocation indexes")
+}
+
+func TestGeolocationDataProcessor_Process_bulk_load_incremental(t *testing.T) {
+	t.Parallel()
+
+	processor := NewParseGeolocationData(mocks.NewGeolocationDataStorage(t), &ctxd.LoggerMock{},
+		WithIncremental(mocks.NewGeolocationDataMerger(t)),
+		WithBulkLoad(mocks.NewGeolocationDataIndexer(t)),
+	)
+
+	_, err := processor.Process(context.Background(), mocks.NewGeolocationDataReader(t), 1)
+	require.ErrorIs(t, err, ErrBulkLoadMode)
+}
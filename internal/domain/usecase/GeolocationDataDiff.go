@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+)
+
+//go:generate mockery --name=GeolocationDataLister --outpkg=mocks --output=mocks --filename=geolocation_data_lister.go --with-expecter
+
+// GeolocationDataLister is the interface that provides the ability to list all the stored geolocation data.
+type GeolocationDataLister interface {
+	// ListGeolocation calls fn for each stored geolocation data. It stops at the first error returned by fn.
+	ListGeolocation(ctx context.Context, fn func(geo model.Geolocation) error) error
+}
+
+// GeolocationChange describes the changes of the geolocation data of an IP address.
+type GeolocationChange struct {
+	IPAddress string              `json:"ip_address" yaml:"ip_address"`
+	Changes   []model.FieldChange `json:"changes" yaml:"changes"`
+}
+
+// Diff is the difference between the geolocation data of a source and the stored one.
+type Diff struct {
+	Added     []model.Geolocation `json:"added" yaml:"added"`
+	Removed   []model.Geolocation `json:"removed" yaml:"removed"`
+	Changed   []GeolocationChange `json:"changed" yaml:"changed"`
+	Unchanged int                 `json:"unchanged" yaml:"unchanged"`
+	// Discarded is the number of records of the source discarded for being invalid or duplicated.
+	Discarded int `json:"discarded" yaml:"discarded"`
+}
+
+// GeolocationDataDiffer compares the geolocation data of a source against the stored one.
+//
+// The geolocation data of the source is decoded, validated and deduplicated as GeolocationDataProcessor does, so that
+// the differences are the ones the processing would write.
+type GeolocationDataDiffer struct {
+	lister GeolocationDataLister
+
+	duplicatePolicy DuplicatePolicy
+	decodeOpts      []model.DecodeOption
+	rules           *model.RuleSet
+
+	logger ctxd.Logger
+}
+
+// DifferOption sets up GeolocationDataDiffer.
+type DifferOption func(d *GeolocationDataDiffer)
+
+// WithDiffDuplicatePolicy sets which geolocation data is compared when an IP address is found more than once, see
+// WithDuplicatePolicy. Defaults to FirstWins.
+func WithDiffDuplicatePolicy(policy DuplicatePolicy) DifferOption {
+	return func(d *GeolocationDataDiffer) {
+		if policy != "" {
+			d.duplicatePolicy = policy
+		}
+	}
+}
+
+// WithDiffDecodeOptions sets the options decoding the geolocation data, see WithDecodeOptions.
+func WithDiffDecodeOptions(opts ...model.DecodeOption) DifferOption {
+	return func(d *GeolocationDataDiffer) {
+		d.decodeOpts = append(d.decodeOpts, opts...)
+	}
+}
+
+// WithDiffRules sets the rules validating the geolocation data, see WithRules. Defaults to model.DefaultRuleSet.
+func WithDiffRules(rules *model.RuleSet) DifferOption {
+	return func(d *GeolocationDataDiffer) {
+		if rules != nil {
+			d.rules = rules
+		}
+	}
+}
+
+// NewGeolocationDataDiffer creates a new GeolocationDataDiffer.
+//
+// The options must match the ones used to parse the stored geolocation data.
+func NewGeolocationDataDiffer(lister GeolocationDataLister, logger ctxd.Logger, opts ...DifferOption) *GeolocationDataDiffer {
+	d := &GeolocationDataDiffer{
+		lister:          lister,
+		duplicatePolicy: FirstWins,
+		rules:           model.DefaultRuleSet(),
+		logger:          logger,
+	}
+
+	for _, o := range opts {
+		o(d)
+	}
+
+	return d
+}
+
+// Diff compares the geolocation data from the given reader against the stored one.
+//
+// The geolocation data of the source is held in memory. When an IP address is found more than once, the geolocation
+// data compared is the one kept by the duplicate policy.
+func (d *GeolocationDataDiffer) Diff(ctx context.Context, reader GeolocationDataReader) (Diff, error) {
+	var diff Diff
+
+	data, err := reader.ReadGeolocationData(ctx)
+	if err != nil {
+		return diff, err
+	}
+
+	decodeOpts, err := sourceDecodeOptions(reader, d.decodeOpts)
+	if err != nil {
+		return diff, ctxd.WrapError(ctx, err, "mapping the columns of the geolocation data")
+	}
+
+	candidates := make(map[string]model.Geolocation)
+
+	for record := range data {
+		geo, err := model.DecodeGeolocation(record, decodeOpts...) //nolint:contextcheck
+		if err == nil {
+			err = d.rules.Check(geo) //nolint:contextcheck
+		}
+
+		if err != nil {
+			diff.Discarded++
+
+			d.logger.Debug(ctx, "invalid geolocation data", "error", err)
+
+			continue
+		}
+
+		// The IP address is canonical once decoded, as the one of the stored geolocation data.
+		if _, ok := candidates[geo.IPAddress]; ok {
+			diff.Discarded++
+
+			if d.duplicatePolicy == LastWins {
+				candidates[geo.IPAddress] = geo
+			}
+
+			continue
+		}
+
+		candidates[geo.IPAddress] = geo
+	}
+
+	if err := ctx.Err(); err != nil {
+		return diff, err
+	}
+
+	err = d.lister.ListGeolocation(ctx, func(stored model.Geolocation) error {
+		candidate, ok := candidates[stored.IPAddress]
+		if !ok {
+			diff.Removed = append(diff.Removed, stored)
+
+			return nil
+		}
+
+		delete(candidates, stored.IPAddress)
+
+		changes := stored.Diff(candidate)
+		if len(changes) == 0 {
+			diff.Unchanged++
+
+			return nil
+		}
+
+		diff.Changed = append(diff.Changed, GeolocationChange{
+			IPAddress: stored.IPAddress,
+			Changes:   changes,
+		})
+
+		return nil
+	})
+	if err != nil {
+		return diff, ctxd.WrapError(ctx, err, "listing stored geolocation data")
+	}
+
+	for _, geo := range candidates {
+		diff.Added = append(diff.Added, geo)
+	}
+
+	sort.Slice(diff.Added, func(i, j int) bool { return diff.Added[i].IPAddress < diff.Added[j].IPAddress })
+	sort.Slice(diff.Removed, func(i, j int) bool { return diff.Removed[i].IPAddress < diff.Removed[j].IPAddress })
+	sort.Slice(diff.Changed, func(i, j int) bool { return diff.Changed[i].IPAddress < diff.Changed[j].IPAddress })
+
+	return diff, nil
+}
@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/domain/usecase/mocks"
+	"github.com/dohernandez/vio/internal/platform/helpers"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeolocationDataDiffer_Diff(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// Stored: 200.106.141.15 unchanged, 160.103.7.140 changed and 1.1.1.1 removed.
+	unchanged, err := model.DecodeGeolocation(data[0])
+	require.NoError(t, err)
+
+	changed, err := model.DecodeGeolocation(data[1])
+	require.NoError(t, err)
+
+	changed.City = "Old Neva"
+
+	removed := model.Geolocation{IPAddress: "1.1.1.1", CountryCode: "AU", Country: "Australia", City: "Sydney"}
+
+	lister := mocks.NewGeolocationDataLister(t)
+	lister.EXPECT().ListGeolocation(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(geo model.Geolocation) error) error {
+			for _, geo := range []model.Geolocation{unchanged, changed, removed} {
+				if err := fn(geo); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	differ := NewGeolocationDataDiffer(lister, &ctxd.LoggerMock{})
+
+	diff, err := differ.Diff(context.Background(), reader)
+	require.NoError(t, err)
+
+	added := make([]string, 0, len(diff.Added))
+
+	for _, geo := range diff.Added {
+		added = append(added, geo.IPAddress)
+	}
+
+	require.Equal(t, []string{"125.159.20.54", "70.95.73.73"}, added)
+	require.Equal(t, []model.Geolocation{removed}, diff.Removed)
+	require.Equal(t, []GeolocationChange{
+		{
+			IPAddress: changed.IPAddress,
+			Changes:   []model.FieldChange{{Field: "city", Old: "Old Neva", New: "New Neva"}},
+		},
+	}, diff.Changed)
+	require.Equal(t, 1, diff.Unchanged)
+	require.Equal(t, 1, diff.Discarded)
+}
+
+func TestGeolocationDataDiffer_Diff_rules_and_duplicates(t *testing.T) {
+	t.Parallel()
+
+	data := [][]string{
+		{"200.106.141.15", "SI", "Nepal", "DuBuquemouth", "-84.87503094689836", "7.206435933364332", "7823011346"},
+		{"200.106.141.15", "SI", "Nepal", "Gradymouth", "-84.87503094689836", "7.206435933364332", "7823011346"},
+		{"10.0.0.1", "SI", "Nepal", "Ljubljana", "46.05", "14.50", "7823011346"},
+		{"160.103.7.140", "CZ", "Nicaragua", "", "-68.31023296602508", "-37.62435199624531", "7301823115"},
+	}
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).
+		RunAndReturn(func(_ context.Context) (<-chan []string, error) {
+			dataCh := make(chan []string, len(data))
+
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+
+			return dataCh, nil
+		})
+
+	lister := mocks.NewGeolocationDataLister(t)
+	lister.EXPECT().ListGeolocation(mock.Anything, mock.Anything).Return(nil)
+
+	// The private IP address is rejected, the missing city only warned about.
+	rules, err := model.NewRuleSet(
+		model.RuleConfig{Name: model.RulePublicIP, Severity: model.SeverityReject},
+		model.RuleConfig{Name: model.RuleCity, Severity: model.SeverityWarn},
+	)
+	require.NoError(t, err)
+
+	differ := NewGeolocationDataDiffer(lister, &ctxd.LoggerMock{},
+		WithDiffRules(rules),
+		WithDiffDuplicatePolicy(LastWins),
+	)
+
+	diff, err := differ.Diff(context.Background(), reader)
+	require.NoError(t, err)
+
+	require.Len(t, diff.Added, 2)
+	require.Equal(t, "160.103.7.140", diff.Added[0].IPAddress)
+	require.Equal(t, "200.106.141.15", diff.Added[1].IPAddress)
+	require.Equal(t, "Gradymouth", diff.Added[1].City)
+	require.Equal(t, 2, diff.Discarded)
+}
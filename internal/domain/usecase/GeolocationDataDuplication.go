@@ -0,0 +1,237 @@
+package usecase
+
+import (
+	"context"
+	"hash/maphash"
+	"net/netip"
+	"sync"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+)
+
+// DuplicatePolicy defines which geolocation data is kept when the same IP address is found more than once.
+type DuplicatePolicy string
+
+const (
+	// FirstWins keeps the first geolocation data read for an IP address.
+	// The geolocation data is saved as soon as it is processed, only the IP address is kept, in memory or spilled to
+	// disk, see WithDuplicationSpill.
+	FirstWins DuplicatePolicy = "first"
+	// LastWins keeps the last geolocation data read for an IP address.
+	// The geolocation data is held in memory until the whole source is processed, the memory is not bounded: it grows
+	// with the number of distinct IP addresses of the source. It is not spilled to disk.
+	LastWins DuplicatePolicy = "last"
+)
+
+// duplicationShards is the number of shards of the duplication helper. It must be a power of two.
+const duplicationShards = 64
+
+// ipKey is the compact representation of an IP address, IPv4 addresses are represented as IPv4-mapped IPv6.
+//
+// The key is canonical, every text form of an IP address gets the same key: 2001:DB8::1 and 2001:db8:0:0::1. The
+// IPv4-mapped IPv6 addresses are canonicalised according to the model.MappedIPv4Policy: 1.2.3.4 and ::ffff:1.2.3.4
+// get the same key with model.UnmapIPv4, but not with model.KeepMappedIPv4. The zone is ignored.
+type ipKey struct {
+	addr [16]byte
+	// is4 tells the plain IPv4 address from its IPv4-mapped IPv6 form.
+	is4 bool
+}
+
+// duplication is a helper to check the duplication of geolocation data loaded.
+// It keeps the uniqueness of the geolocation data by IP address.
+//
+// IP addresses are packed into 16 bytes keys, spread across shards with their own lock so the processor workers
+// do not serialise on a single mutex. The IP addresses of FirstWins and keep are spilled to a temporary file past the
+// maximum of keys of the spillOptions, each spilled run along with a bloom filter so that most new IP addresses are
+// not looked up on disk. The file is removed by close.
+type duplication struct {
+	policy DuplicatePolicy
+	mapped model.MappedIPv4Policy
+	seed   maphash.Seed
+
+	spill *spillFile
+	// shardKeys is the maximum of keys held in memory by a shard, zero when not spilled.
+	shardKeys int
+
+	shards [duplicationShards]duplicationShard
+}
+
+type duplicationShard struct {
+	// seen is used by FirstWins.
+	seen ipSet
+	// last is used by LastWins.
+	last map[ipKey]sequenced
+	// kept are the IP addresses set by keep.
+	kept ipSet
+
+	sm sync.Mutex
+}
+
+// sequenced is a geolocation data along with its position in the source.
+type sequenced struct {
+	seq uint64
+	geo *model.Geolocation
+}
+
+func newDuplication(policy DuplicatePolicy, mapped model.MappedIPv4Policy, spill spillOptions) *duplication {
+	d := &duplication{
+		policy: policy,
+		mapped: mapped,
+		seed:   maphash.MakeSeed(),
+		spill:  &spillFile{dir: spill.dir},
+	}
+
+	if spill.maxKeys > 0 {
+		d.shardKeys = max(1, spill.maxKeys/duplicationShards)
+	}
+
+	if policy == LastWins {
+		for i := range d.shards {
+			d.shards[i].last = make(map[ipKey]sequenced)
+		}
+	}
+
+	return d
+}
+
+// close removes the IP addresses spilled to disk.
+func (d *duplication) close() error {
+	return d.spill.close()
+}
+
+func (d *duplication) key(ip string) (ipKey, *duplicationShard, error) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ipKey{}, nil, ctxd.WrapError(context.Background(), err, "parsing ip address", "ip_address", ip)
+	}
+
+	if d.mapped != model.KeepMappedIPv4 {
+		addr = addr.Unmap()
+	}
+
+	k := ipKey{addr: addr.As16(), is4: addr.Is4()}
+
+	return k, &d.shards[maphash.Bytes(d.seed, k.addr[:])&(duplicationShards-1)], nil
+}
+
+// check checks whether the geolocation data at the position seq of the source is a duplicate.
+//
+// Returns true when the geolocation data is ready to be saved. With LastWins the geolocation data is held until
+// drain is called. Returns ErrGeolocationAlreadyExists when a geolocation data is discarded as duplicate.
+func (d *duplication) check(geo *model.Geolocation, seq uint64) (bool, error) {
+	k, shard, err := d.key(geo.IPAddress)
+	if err != nil {
+		return false, err
+	}
+
+	shard.sm.Lock()
+	defer shard.sm.Unlock()
+
+	if d.policy != LastWins {
+		found, err := shard.seen.contains(k, d.spill)
+		if err != nil {
+			return false, ctxd.WrapError(context.Background(), err, "looking up spilled ip address", "ip_address", geo.IPAddress)
+		}
+
+		if found {
+			return false, model.ErrGeolocationAlreadyExists
+		}
+
+		if err := shard.seen.add(k, d.spill, d.shardKeys, d.seed); err != nil {
+			return false, ctxd.WrapError(context.Background(), err, "spilling ip addresses", "ip_address", geo.IPAddress)
+		}
+
+		return true, nil
+	}
+
+	held, ok := shard.last[k]
+	if ok && held.seq > seq {
+		return false, model.ErrGeolocationAlreadyExists
+	}
+
+	shard.last[k] = sequenced{seq: seq, geo: geo}
+
+	if ok {
+		return false, model.ErrGeolocationAlreadyExists
+	}
+
+	return false, nil
+}
+
+// keep records the IP address, to be found by contains. An invalid IP address is ignored.
+func (d *duplication) keep(ip string) error {
+	k, shard, err := d.key(ip)
+	if err != nil {
+		// An invalid IP address is not stored, there is nothing to find.
+		return nil //nolint:nilerr
+	}
+
+	shard.sm.Lock()
+	defer shard.sm.Unlock()
+
+	found, err := shard.kept.contains(k, d.spill)
+	if err != nil {
+		return ctxd.WrapError(context.Background(), err, "looking up spilled ip address", "ip_address", ip)
+	}
+
+	if found {
+		return nil
+	}
+
+	if err := shard.kept.add(k, d.spill, d.shardKeys, d.seed); err != nil {
+		return ctxd.WrapError(context.Background(), err, "spilling ip addresses", "ip_address", ip)
+	}
+
+	return nil
+}
+
+// contains checks whether the IP address was found, or kept.
+func (d *duplication) contains(ip string) (bool, error) {
+	k, shard, err := d.key(ip)
+	if err != nil {
+		return false, err
+	}
+
+	shard.sm.Lock()
+	defer shard.sm.Unlock()
+
+	if ok, err := shard.kept.contains(k, d.spill); err != nil || ok {
+		return ok, err
+	}
+
+	if d.policy == LastWins {
+		_, ok := shard.last[k]
+
+		return ok, nil
+	}
+
+	return shard.seen.contains(k, d.spill)
+}
+
+// drain calls fn for each geolocation data held by LastWins, releasing it.
+// It stops when fn returns false.
+func (d *duplication) drain(fn func(geo *model.Geolocation) bool) {
+	for i := range d.shards {
+		shard := &d.shards[i]
+
+		shard.sm.Lock()
+
+		for k, held := range shard.last {
+			if held.geo == nil {
+				continue
+			}
+
+			// Keep the IP address known, the geolocation data is no longer needed.
+			shard.last[k] = sequenced{seq: held.seq}
+
+			if !fn(held.geo) {
+				shard.sm.Unlock()
+
+				return
+			}
+		}
+
+		shard.sm.Unlock()
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplication_check_first_wins(t *testing.T) {
+	t.Parallel()
+
+	dupl := newDuplication(FirstWins, model.UnmapIPv4, spillOptions{})
+
+	first := &model.Geolocation{IPAddress: "200.106.141.15", City: "DuBuquemouth"}
+	second := &model.Geolocation{IPAddress: "200.106.141.15", City: "New Neva"}
+
+	isReady, err := dupl.check(first, 1)
+	require.NoError(t, err)
+	require.True(t, isReady)
+
+	isReady, err = dupl.check(second, 2)
+	require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists)
+	require.False(t, isReady)
+
+	var drained []*model.Geolocation
+
+	dupl.drain(func(geo *model.Geolocation) bool {
+		drained = append(drained, geo)
+
+		return true
+	})
+
+	require.Empty(t, drained)
+}
+
+func TestDuplication_check_last_wins(t *testing.T) {
+	t.Parallel()
+
+	dupl := newDuplication(LastWins, model.UnmapIPv4, spillOptions{})
+
+	first := &model.Geolocation{IPAddress: "200.106.141.15", City: "DuBuquemouth"}
+	second := &model.Geolocation{IPAddress: "200.106.141.15", City: "New Neva"}
+	third := &model.Geolocation{IPAddress: "200.106.141.15", City: "Gradymouth"}
+	other := &model.Geolocation{IPAddress: "2001:db8::1", City: "Port Karson"}
+
+	isReady, err := dupl.check(first, 1)
+	require.NoError(t, err)
+	require.False(t, isReady)
+
+	// Processed out of order, the third one is still the last one read.
+	isReady, err = dupl.check(third, 3)
+	require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists)
+	require.False(t, isReady)
+
+	isReady, err = dupl.check(second, 2)
+	require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists)
+	require.False(t, isReady)
+
+	isReady, err = dupl.check(other, 4)
+	require.NoError(t, err)
+	require.False(t, isReady)
+
+	var drained []*model.Geolocation
+
+	dupl.drain(func(geo *model.Geolocation) bool {
+		drained = append(drained, geo)
+
+		return true
+	})
+
+	require.ElementsMatch(t, []*model.Geolocation{third, other}, drained)
+
+	// Drained geolocation data are not drained twice.
+	dupl.drain(func(_ *model.Geolocation) bool {
+		t.Fatal("unexpected geolocation data drained")
+
+		return false
+	})
+}
+
+func TestDuplication_check_invalid_ip(t *testing.T) {
+	t.Parallel()
+
+	dupl := newDuplication(FirstWins, model.UnmapIPv4, spillOptions{})
+
+	_, err := dupl.check(&model.Geolocation{IPAddress: "invalid"}, 1)
+	require.ErrorContains(t, err, "parsing ip address")
+}
+
+func TestDuplication_check_canonical_ip(t *testing.T) {
+	t.Parallel()
+
+	dupl := newDuplication(FirstWins, model.UnmapIPv4, spillOptions{})
+
+	isReady, err := dupl.check(&model.Geolocation{IPAddress: "2001:DB8::1"}, 1)
+	require.NoError(t, err)
+	require.True(t, isReady)
+
+	_, err = dupl.check(&model.Geolocation{IPAddress: "2001:db8:0:0::1"}, 2)
+	require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists)
+
+	isReady, err = dupl.check(&model.Geolocation{IPAddress: "::ffff:70.95.73.73"}, 3)
+	require.NoError(t, err)
+	require.True(t, isReady)
+
+	_, err = dupl.check(&model.Geolocation{IPAddress: "70.95.73.73"}, 4)
+	require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists)
+
+	found, err := dupl.contains("2001:db8::1")
+	require.NoError(t, err)
+	require.True(t, found)
+}
+
+func TestDuplication_check_keep_mapped_ipv4(t *testing.T) {
+	t.Parallel()
+
+	dupl := newDuplication(FirstWins, model.KeepMappedIPv4, spillOptions{})
+
+	isReady, err := dupl.check(&model.Geolocation{IPAddress: "::ffff:70.95.73.73"}, 1)
+	require.NoError(t, err)
+	require.True(t, isReady)
+
+	isReady, err = dupl.check(&model.Geolocation{IPAddress: "70.95.73.73"}, 2)
+	require.NoError(t, err)
+	require.True(t, isReady)
+
+	_, err = dupl.check(&model.Geolocation{IPAddress: "::FFFF:70.95.73.73"}, 3)
+	require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists)
+
+	dupl = newDuplication(FirstWins, model.KeepMappedIPv4, spillOptions{})
+
+	_, err = dupl.check(&model.Geolocation{IPAddress: "70.95.73.73"}, 1)
+	require.NoError(t, err)
+
+	found, err := dupl.contains("::ffff:70.95.73.73")
+	require.NoError(t, err)
+	require.False(t, found)
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -10,8 +11,6 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
-const batchBuffer = 500
-
 //go:generate mockery --name=GeolocationDataReader --outpkg=mocks --output=mocks --filename=geolocation_data_reader.go --with-expecter
 
 // GeolocationDataReader is the interface that provides the ability to read geolocation data.
@@ -20,6 +19,34 @@ type GeolocationDataReader interface {
 	ReadGeolocationData(ctx context.Context) (<-chan []string, error)
 }
 
+// GeolocationDataHeader is the interface a GeolocationDataReader can optionally implement to provide the header of
+// the source, once ReadGeolocationData is called. The records are then decoded by column name instead of position.
+type GeolocationDataHeader interface {
+	Header() []string
+}
+
+// sourceDecodeOptions returns the options decoding the records of the reader, mapping the columns of its header if
+// any.
+//
+// A header of model.InputFieldNum columns not naming them is read by position, as the legacy input data.
+func sourceDecodeOptions(reader GeolocationDataReader, opts []model.DecodeOption) ([]model.DecodeOption, error) {
+	h, ok := reader.(GeolocationDataHeader)
+	if !ok || h.Header() == nil {
+		return opts, nil
+	}
+
+	columns, err := model.NewColumns(h.Header())
+	if err != nil {
+		if len(h.Header()) == model.InputFieldNum {
+			return opts, nil
+		}
+
+		return nil, err
+	}
+
+	return append(append([]model.DecodeOption(nil), opts...), model.WithColumns(columns)), nil
+}
+
 //go:generate mockery --name=GeolocationDataStorage --outpkg=mocks --output=mocks --filename=geolocation_data_storage.go --with-expecter
 
 // GeolocationDataStorage is the interface that provides the ability to save geolocation data.
@@ -27,28 +54,223 @@ type GeolocationDataStorage interface {
 	SaveGeolocation(ctx context.Context, geo []*model.Geolocation) error
 }
 
+//go:generate mockery --name=GeolocationDataMerger --outpkg=mocks --output=mocks --filename=geolocation_data_merger.go --with-expecter
+
+// GeolocationDataMerger is the interface that provides the ability to merge geolocation data with the stored one.
+type GeolocationDataMerger interface {
+	// FindGeolocationsByIP returns the stored geolocation data of the given IPs, indexed by IP.
+	FindGeolocationsByIP(ctx context.Context, ips []string) (map[string]model.Geolocation, error)
+	// MergeGeolocation saves the new geolocation data and updates the changed one, all or nothing.
+	MergeGeolocation(ctx context.Context, added, changed []*model.Geolocation) error
+}
+
 // GeolocationDataProcessor processes the geolocation data.
 type GeolocationDataProcessor struct {
 	storage GeolocationDataStorage
+	// merger is set in incremental mode, only new and changed geolocation data is written.
+	merger GeolocationDataMerger
+	// syncer is set in sync mode, stored geolocation data not found in the source is removed.
+	syncer   GeolocationDataSyncer
+	syncOpts SyncOptions
+	// versioner is set when each processing creates a dataset version.
+	versioner GeolocationDataVersioner
+	// retry configures the retries of the batches failing to be saved.
+	retry RetryOptions
+	// indexer is set in bulk load mode, the indexes are dropped while the geolocation data is inserted.
+	indexer GeolocationDataIndexer
+
+	batchSize int
+	// maxBatchSize is the maximum number of geolocation data the storage saves at once.
+	maxBatchSize int
+	saverWorkers int
+	readyBuffer  int
+	// batchLatency is the target insert latency to auto-tune the batch size. Zero disables auto-tuning.
+	batchLatency time.Duration
+
+	duplicatePolicy DuplicatePolicy
+	spill           spillOptions
+	decodeOpts      []model.DecodeOption
+	rules           *model.RuleSet
+
+	progress         ProgressFunc
+	progressInterval time.Duration
 
 	logger ctxd.Logger
 }
 
+// ProcessorOption sets up GeolocationDataProcessor.
+type ProcessorOption func(p *GeolocationDataProcessor)
+
+// WithProgress reports the progress of the processing every interval to fn.
+func WithProgress(interval time.Duration, fn ProgressFunc) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.progressInterval = interval
+		p.progress = fn
+	}
+}
+
+// WithBatchSize sets the number of geolocation data saved at once, up to the maximum of the storage, see
+// GeolocationDataBatchLimiter. Defaults to 500.
+//
+// When auto-tuning is enabled, it is the initial batch size.
+func WithBatchSize(size int) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		if size > 0 {
+			p.batchSize = size
+		}
+	}
+}
+
+// WithSaverWorkers sets the number of workers saving geolocation data in parallel. Defaults to 15.
+func WithSaverWorkers(workers int) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		if workers > 0 {
+			p.saverWorkers = workers
+		}
+	}
+}
+
+// WithReadyBuffer sets the buffer size of the channel holding the geolocation data ready to be saved.
+// Defaults to twice the batch size times the number of saver workers.
+func WithReadyBuffer(size int) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		if size > 0 {
+			p.readyBuffer = size
+		}
+	}
+}
+
+// WithBatchAutoTune enables auto-tuning of the batch size, aiming for the given insert latency.
+func WithBatchAutoTune(latency time.Duration) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.batchLatency = latency
+	}
+}
+
+// WithDuplicatePolicy sets which geolocation data is kept when an IP address is found more than once.
+// Defaults to FirstWins.
+func WithDuplicatePolicy(policy DuplicatePolicy) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		if policy != "" {
+			p.duplicatePolicy = policy
+		}
+	}
+}
+
+// WithDuplicationSpill bounds the memory finding the duplicates: once maxKeys IP addresses are held in memory, they
+// are spilled to a temporary file in dir, the default directory for temporary files when empty. Applies to FirstWins
+// and to the IP addresses of the source found by the sync mode, LastWins holds the geolocation data in memory anyway.
+//
+// Each spill keeps a bloom filter in memory, about 10 bits per IP address, so that most new IP addresses are not
+// looked up on disk. Defaults to keeping all the IP addresses in memory.
+func WithDuplicationSpill(dir string, maxKeys int) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.spill = spillOptions{dir: dir, maxKeys: maxKeys}
+	}
+}
+
+// WithDecodeOptions sets the options decoding the geolocation data, i.e. model.WithMappedIPv4Policy.
+func WithDecodeOptions(opts ...model.DecodeOption) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.decodeOpts = append(p.decodeOpts, opts...)
+	}
+}
+
+// WithRules sets the rules validating the geolocation data. Defaults to model.DefaultRuleSet.
+func WithRules(rules *model.RuleSet) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		if rules != nil {
+			p.rules = rules
+		}
+	}
+}
+
+// WithIncremental enables the incremental mode.
+//
+// The geolocation data is compared against the stored one and classified as new, changed or unchanged. Only new
+// geolocation data is inserted and changed one updated.
+func WithIncremental(merger GeolocationDataMerger) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.merger = merger
+	}
+}
+
+// WithSync enables the sync mode, the source is a full snapshot of the geolocation data.
+//
+// The geolocation data is merged as in incremental mode. The source is read twice: first to find the stored geolocation
+// data which IP address is not in the source, aborting before anything is written when too much of it would be
+// removed, then to load it. Once the whole source is successfully saved, the stale geolocation data is removed. The
+// stored geolocation data of an IP address found in the source is kept even when the source one is rejected.
+func WithSync(merger GeolocationDataMerger, syncer GeolocationDataSyncer, opts SyncOptions) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.merger = merger
+		p.syncer = syncer
+		p.syncOpts = opts
+	}
+}
+
 // NewParseGeolocationData creates a new GeolocationDataProcessor.
-func NewParseGeolocationData(storage GeolocationDataStorage, logger ctxd.Logger) *GeolocationDataProcessor {
-	return &GeolocationDataProcessor{
-		storage: storage,
-		logger:  logger,
+func NewParseGeolocationData(storage GeolocationDataStorage, logger ctxd.Logger, opts ...ProcessorOption) *GeolocationDataProcessor {
+	p := &GeolocationDataProcessor{
+		storage:         storage,
+		batchSize:       defaultBatchSize,
+		maxBatchSize:    maxBatchSize(storage),
+		saverWorkers:    defaultSaverWorkers,
+		duplicatePolicy: FirstWins,
+		rules:           model.DefaultRuleSet(),
+		logger:          logger,
+	}
+
+	for _, o := range opts {
+		o(p)
 	}
+
+	p.batchSize = min(p.batchSize, p.maxBatchSize)
+
+	return p
 }
 
 // Process processes the geolocation data from the given reader in parallel.
-func (p *GeolocationDataProcessor) Process(ctx context.Context, reader GeolocationDataReader, inParallel uint) error {
+//
+// Returns the Report summarizing the processing result.
+func (p *GeolocationDataProcessor) Process(ctx context.Context, reader GeolocationDataReader, inParallel uint) (Report, error) {
+	if p.indexer != nil {
+		return p.bulkLoad(ctx, reader, inParallel)
+	}
+
+	return p.load(ctx, reader, inParallel)
+}
+
+// load processes the geolocation data from the given reader in parallel.
+func (p *GeolocationDataProcessor) load(ctx context.Context, reader GeolocationDataReader, inParallel uint) (Report, error) {
 	startTime := time.Now()
 
-	data, err := reader.ReadGeolocationData(ctx)
+	var (
+		stale      []string
+		syncReport SyncReport
+		err        error
+	)
+
+	// The stale geolocation data is found ahead of the load, so that the sync is aborted before anything is written.
+	if p.syncer != nil {
+		stale, syncReport, err = p.staleGeolocation(ctx, reader)
+		if err != nil {
+			return Report{}, err
+		}
+	}
+
+	// The reader is stopped when the processing ends early.
+	readCtx, stopReading := context.WithCancel(ctx)
+	defer stopReading()
+
+	data, err := reader.ReadGeolocationData(readCtx)
 	if err != nil {
-		return err
+		return Report{}, err
+	}
+
+	run, err := p.newRun(ctx, reader)
+	if err != nil {
+		return Report{}, err
 	}
 
 	eg, egctx := errgroup.WithContext(ctx)
@@ -57,17 +279,37 @@ func (p *GeolocationDataProcessor) Process(ctx context.Context, reader Geolocati
 		report = &reporter{
 			eg: eg,
 		}
-		dupl = &duplication{}
+		dupl = newDuplication(p.duplicatePolicy, model.DecodeMappedIPv4Policy(p.decodeOpts...), p.spill)
 
 		processWorker = inParallel
-		saverWorker   = 15
+		saverWorker   = p.saverWorkers
 		// ready is a channel to send geolocation data to be saved.
-		// The buffer size is twice the number of saver workers.
+		// The buffer size is by default twice the batch size times the number of saver workers.
 		// This is to ensure that the processor worker can continue to process the data while the saver worker(s) is/are
 		// still saving the data.
-		ready = make(chan *model.Geolocation, batchBuffer*saverWorker*2)
+		readyBuffer = p.readyBuffer
 	)
 
+	defer p.closeDuplication(ctx, dupl)
+
+	if readyBuffer == 0 {
+		readyBuffer = p.batchSize * saverWorker * 2
+	}
+
+	ready := make(chan *model.Geolocation, readyBuffer)
+
+	// records are the geolocation data read along with their position in the source.
+	records := make(chan sequencedRecord, processWorker)
+
+	// Sequence the geolocation data read.
+	eg.Go(func() error {
+		defer close(records)
+
+		p.sequence(egctx, data, records, report)
+
+		return nil
+	})
+
 	// Start the saver worker(s).
 	eg.Go(func() error {
 		var wg sync.WaitGroup
@@ -101,48 +343,231 @@ func (p *GeolocationDataProcessor) Process(ctx context.Context, reader Geolocati
 			go func() {
 				defer wg.Done()
 
-				p.process(egctx, data, ready, dupl, report)
+				p.process(egctx, records, ready, dupl, report, run)
 			}()
 		}
 
 		wg.Wait()
 
+		// Geolocation data held to keep the last one read are ready now that the whole source is processed.
+		dupl.drain(func(geo *model.Geolocation) bool {
+			select {
+			case <-egctx.Done():
+				return false
+			case ready <- geo:
+				return true
+			}
+		})
+
 		return nil
 	})
 
-	if err := eg.Wait(); err != nil {
-		return ctxd.NewError(ctx, "processing geolocation data", "error", err)
+	var progress *progressTracker
+
+	if p.progress != nil && p.progressInterval > 0 {
+		progress = &progressTracker{
+			fn:        p.progress,
+			interval:  p.progressInterval,
+			r:         report,
+			reader:    reader,
+			startTime: startTime,
+		}
+
+		progress.start(ctx)
+	}
+
+	err = eg.Wait()
+
+	if progress != nil {
+		progress.stop(ctx)
+	}
+
+	if err != nil {
+		return Report{}, ctxd.NewError(ctx, "processing geolocation data", "error", err)
+	}
+
+	// Geolocation data in flight when canceled is neither accepted nor discarded.
+	if err := ctx.Err(); err != nil {
+		return Report{}, ctxd.WrapError(ctx, err, "processing geolocation data canceled")
+	}
+
+	if report.read != report.accepted+report.discarded {
+		return Report{}, ctxd.NewError(ctx, "geolocation data unaccounted for",
+			"read", report.read,
+			"accepted", report.accepted,
+			"discarded", report.discarded,
+		)
+	}
+
+	if p.syncer != nil {
+		if report.saveFailures > 0 {
+			return Report{}, ctxd.NewError(ctx, "sync aborted, geolocation data failed to be saved",
+				"failures", report.saveFailures,
+			)
+		}
+
+		if err := p.prune(ctx, stale); err != nil {
+			return Report{}, err
+		}
+	}
+
+	if p.versioner != nil {
+		if err := p.versioner.CompleteDatasetVersion(ctx, run.version, report.accepted); err != nil {
+			return Report{}, ctxd.WrapError(ctx, err, "completing the dataset version", "version", run.version)
+		}
 	}
 
 	endTime := time.Since(startTime)
 
-	p.logger.Important(ctx, "geolocation data processed",
+	kv := []any{
+		"read", report.read,
 		"accepted", report.accepted,
 		"discarded", report.discarded,
 		"discarded_reasons", report.discardedReasons,
 		"duration_s", endTime.Seconds(),
+	}
+
+	if p.versioner != nil {
+		kv = append(kv, "version", run.version)
+	}
+
+	if p.merger != nil {
+		kv = append(kv,
+			"new", report.added,
+			"changed", report.changed,
+			"unchanged", report.unchanged,
+		)
+	}
+
+	if p.syncer != nil {
+		kv = append(kv,
+			"stale", syncReport.Stale,
+			"soft_deleted", syncReport.SoftDeleted,
+		)
+	}
+
+	p.logger.Important(ctx, "geolocation data processed", kv...)
+
+	res := newReport(report, reader, startTime, endTime)
+	res.Version = run.version
+
+	if p.merger != nil {
+		res.Merge = &MergeReport{
+			New:       report.added,
+			Changed:   report.changed,
+			Unchanged: report.unchanged,
+		}
+	}
+
+	if p.syncer != nil {
+		res.Sync = &syncReport
+	}
+
+	return res, nil
+}
+
+// processRun describes a processing run, once the source is open.
+type processRun struct {
+	decodeOpts []model.DecodeOption
+	// version is the dataset version created by the run, zero when versioning is disabled.
+	version    int64
+	sourceFile string
+	// headerLines is the number of lines before the first record of the source.
+	headerLines uint64
+}
+
+// newRun starts the processing run of the reader.
+func (p *GeolocationDataProcessor) newRun(ctx context.Context, reader GeolocationDataReader) (processRun, error) {
+	var (
+		run processRun
+		err error
 	)
 
-	return nil
+	run.decodeOpts, err = sourceDecodeOptions(reader, p.decodeOpts)
+	if err != nil {
+		return run, ctxd.WrapError(ctx, err, "mapping the columns of the geolocation data")
+	}
+
+	if h, ok := reader.(GeolocationDataHeader); ok && h.Header() != nil {
+		run.headerLines = 1
+	}
+
+	if s, ok := reader.(GeolocationDataSource); ok {
+		run.sourceFile = s.SourceInfo().Name
+	}
+
+	if p.versioner != nil {
+		run.version, err = p.versioner.CreateDatasetVersion(ctx, run.sourceFile)
+		if err != nil {
+			return run, ctxd.WrapError(ctx, err, "creating the dataset version")
+		}
+	}
+
+	return run, nil
 }
 
-func (p *GeolocationDataProcessor) process(
+// sequencedRecord is a geolocation data record along with its position in the source.
+type sequencedRecord struct {
+	seq  uint64
+	data []string
+}
+
+// closeDuplication removes the IP addresses spilled to disk by the duplication helper.
+func (p *GeolocationDataProcessor) closeDuplication(ctx context.Context, dupl *duplication) {
+	if err := dupl.close(); err != nil {
+		p.logger.Warn(ctx, "failed to remove the spilled ip addresses", "error", err)
+	}
+}
+
+// sequence numbers the geolocation data in the order it is read.
+func (p *GeolocationDataProcessor) sequence(
 	ctx context.Context,
 	data <-chan []string,
+	records chan<- sequencedRecord,
+	r *reporter,
+) {
+	var seq uint64
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d, ok := <-data:
+			if !ok {
+				return
+			}
+
+			r.readOne()
+
+			seq++
+
+			select {
+			case <-ctx.Done():
+				return
+			case records <- sequencedRecord{seq: seq, data: d}:
+			}
+		}
+	}
+}
+
+func (p *GeolocationDataProcessor) process(
+	ctx context.Context,
+	records <-chan sequencedRecord,
 	ready chan<- *model.Geolocation,
 	dupl *duplication,
 	r *reporter,
+	run processRun,
 ) {
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case d, ok := <-data:
+		case rec, ok := <-records:
 			if !ok {
 				return
 			}
 
-			geo, err := model.DecodeGeolocation(d) //nolint:contextcheck
+			geo, err := model.DecodeGeolocation(rec.data, run.decodeOpts...) //nolint:contextcheck
 			if err != nil {
 				r.failed(err)
 
@@ -151,8 +576,17 @@ func (p *GeolocationDataProcessor) process(
 				continue
 			}
 
+			geo.VersionID = run.version
+			geo.SourceFile = run.sourceFile
+			geo.SourceLine = run.headerLines + rec.seq
+
 			// Validate geolocation data before saving.
-			if err = geo.IsValid(); err != nil { //nolint:contextcheck
+			violations := p.rules.Validate(geo) //nolint:contextcheck
+			r.violated(violations)
+
+			if n := len(violations); n > 0 && violations[n-1].Severity == model.SeverityReject {
+				err = violations[n-1].Err
+
 				r.failed(err)
 
 				p.logger.Debug(ctx, "invalid geolocation data", "error", err)
@@ -160,12 +594,14 @@ func (p *GeolocationDataProcessor) process(
 				continue
 			}
 
-			err = dupl.check(&geo)
+			isReady, err := dupl.check(&geo, rec.seq) //nolint:contextcheck
 			if err != nil {
 				r.failed(err)
 
 				p.logger.Debug(ctx, "geolocation data exists", "error", err)
+			}
 
+			if !isReady {
 				continue
 			}
 
@@ -179,22 +615,39 @@ func (p *GeolocationDataProcessor) save(
 	ready <-chan *model.Geolocation,
 	r *reporter,
 ) {
-	buf := make([]*model.Geolocation, 0, batchBuffer)
+	sizer := newBatchSizer(p.batchSize, p.maxBatchSize, p.batchLatency)
 
-	defer func() {
-		if len(buf) == 0 {
-			return
-		}
+	buf := make([]*model.Geolocation, 0, sizer.size())
 
-		if err := p.storage.SaveGeolocation(ctx, buf); err != nil {
-			r.failed(err)
+	flush := func() {
+		start := time.Now()
 
+		if err := p.writeRetrying(ctx, buf, r); err != nil {
 			p.logger.Debug(ctx, "save geolocation data", "error", err)
 
+			// A failure caused by the data is isolated to the geolocation data at fault.
+			if len(buf) > 1 && ctx.Err() == nil && p.dataError(err) {
+				p.writeRows(ctx, buf, r)
+
+				return
+			}
+
+			r.storageFailed(len(buf))
+
 			return
 		}
 
+		sizer.observe(len(buf), time.Since(start))
+
 		r.succeed(len(buf))
+	}
+
+	defer func() {
+		if len(buf) == 0 {
+			return
+		}
+
+		flush()
 	}()
 
 	for {
@@ -208,37 +661,121 @@ func (p *GeolocationDataProcessor) save(
 
 			buf = append(buf, geo)
 
-			if len(buf) < batchBuffer {
+			if len(buf) < sizer.size() {
 				continue
 			}
 		}
 
-		if err := p.storage.SaveGeolocation(ctx, buf); err != nil {
-			r.failed(err)
+		flush()
 
-			p.logger.Debug(ctx, "save geolocation data", "error", err)
+		// Reset buffer
+		buf = make([]*model.Geolocation, 0, sizer.size())
+	}
+}
 
-			continue
-		}
+// write writes the batch of geolocation data into the storage.
+//
+// In incremental mode, the batch is merged with the stored geolocation data.
+func (p *GeolocationDataProcessor) write(ctx context.Context, buf []*model.Geolocation, r *reporter) error {
+	if p.merger == nil {
+		return p.storage.SaveGeolocation(ctx, buf)
+	}
 
-		r.succeed(len(buf))
+	ips := make([]string, 0, len(buf))
 
-		// Reset buffer
-		buf = make([]*model.Geolocation, 0, batchBuffer)
+	for _, geo := range buf {
+		ips = append(ips, geo.IPAddress)
+	}
+
+	stored, err := p.merger.FindGeolocationsByIP(ctx, ips)
+	if err != nil {
+		return err
+	}
+
+	var (
+		added     []*model.Geolocation
+		changed   []*model.Geolocation
+		unchanged int
+	)
+
+	for _, geo := range buf {
+		s, ok := stored[geo.IPAddress]
+
+		switch {
+		case !ok:
+			added = append(added, geo)
+		case s.Equal(*geo):
+			unchanged++
+		default:
+			changed = append(changed, geo)
+		}
+	}
+
+	// Saved at once, a retry of the batch classifies the geolocation data as the failed attempt did.
+	if len(added) > 0 || len(changed) > 0 {
+		if err := p.merger.MergeGeolocation(ctx, added, changed); err != nil {
+			return err
+		}
 	}
+
+	r.merged(len(added), len(changed), unchanged)
+
+	return nil
 }
 
+// DiscardReasonStorage is the reason of the geolocation data discarded because it failed to be saved, each row of a
+// failed batch is counted.
+const DiscardReasonStorage = "storage"
+
 // reporter is a helper to report the processing result.
 type reporter struct {
+	read             int
 	accepted         int
 	discarded        int
 	discardedReasons map[string]uint
 
+	// added, changed and unchanged are only reported in incremental mode.
+	added     int
+	changed   int
+	unchanged int
+
+	// saveFailures is the number of geolocation data failed to be saved.
+	saveFailures int
+
+	// rules counts the violations by rule.
+	rules map[string]RuleReport
+
 	// eg...
 	eg *errgroup.Group
 
+	smR sync.Mutex
 	smA sync.Mutex
 	smD sync.Mutex
+	smV sync.Mutex
+}
+
+func (r *reporter) readOne() {
+	r.smR.Lock()
+	defer r.smR.Unlock()
+
+	r.read++
+}
+
+// counts returns the current read, accepted and discarded counters.
+func (r *reporter) counts() (read, accepted, discarded int) {
+	r.smR.Lock()
+	read = r.read
+	r.smR.Unlock()
+
+	r.smA.Lock()
+	accepted = r.accepted
+	r.smA.Unlock()
+
+	r.smD.Lock()
+	discarded = r.discarded
+	r.smD.Unlock()
+
+	return read, accepted, discarded
 }
 
 func (r *reporter) succeed(a int) {
@@ -252,46 +789,87 @@ func (r *reporter) succeed(a int) {
 	})
 }
 
-func (r *reporter) failed(err error) {
+func (r *reporter) merged(added, changed, unchanged int) {
+	r.smA.Lock()
+	defer r.smA.Unlock()
+
+	r.added += added
+	r.changed += changed
+	r.unchanged += unchanged
+}
+
+func (r *reporter) violated(violations []model.Violation) {
+	if len(violations) == 0 {
+		return
+	}
+
+	r.smV.Lock()
+	defer r.smV.Unlock()
+
+	if r.rules == nil {
+		r.rules = make(map[string]RuleReport)
+	}
+
+	for _, v := range violations {
+		rr := r.rules[v.Rule]
+
+		if v.Severity == model.SeverityReject {
+			rr.Rejected++
+		} else {
+			rr.Warned++
+		}
+
+		r.rules[v.Rule] = rr
+	}
+}
+
+// storageFailed reports n geolocation data failed to be saved, discarded as DiscardReasonStorage.
+func (r *reporter) storageFailed(n int) {
 	r.eg.Go(func() error {
 		r.smD.Lock()
 		defer r.smD.Unlock()
 
-		r.discarded++
+		r.saveFailures += n
+		r.discarded += n
 
 		if r.discardedReasons == nil {
 			r.discardedReasons = make(map[string]uint)
 		}
 
-		errMsg := err.Error()
-
-		r.discardedReasons[errMsg]++
+		r.discardedReasons[DiscardReasonStorage] += uint(n) //nolint:gosec // n is a batch length.
 
 		return nil
 	})
 }
 
-// duplication is a helper to check the duplication of geolocation data loaded.
-// It keeps the uniqueness of the geolocation data by IP address.
-type duplication struct {
-	uniqueness map[string]bool
+func (r *reporter) failed(err error) {
+	r.eg.Go(func() error {
+		r.smD.Lock()
+		defer r.smD.Unlock()
+
+		r.discarded++
+
+		if r.discardedReasons == nil {
+			r.discardedReasons = make(map[string]uint)
+		}
+
+		r.discardedReasons[discardReason(err)]++
 
-	sm sync.Mutex
+		return nil
+	})
 }
 
-func (d *duplication) check(geo *model.Geolocation) error {
-	d.sm.Lock()
-	defer d.sm.Unlock()
+// discardReason returns the reason the geolocation data is discarded for, the code of the model.ValidationError.
+func discardReason(err error) string {
+	var verr *model.ValidationError
 
-	if d.uniqueness == nil {
-		d.uniqueness = make(map[string]bool)
+	if errors.As(err, &verr) {
+		return string(verr.Code)
 	}
 
-	if _, ok := d.uniqueness[geo.IPAddress]; ok {
-		return model.ErrGeolocationAlreadyExists
+	if errors.Is(err, model.ErrGeolocationAlreadyExists) {
+		return string(model.CodeDuplicateIPAddress)
 	}
 
-	d.uniqueness[geo.IPAddress] = true
-
-	return nil
+	return err.Error()
 }
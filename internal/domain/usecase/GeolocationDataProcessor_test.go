@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/bool64/ctxd"
 	"github.com/dohernandez/vio/internal/domain/model"
@@ -43,7 +47,7 @@ func TestGeolocationDataProcessor_Process_all_success(t *testing.T) {
 	processor := NewParseGeolocationData(storage, logger)
 
 	// Process with 3 parallel processes
-	err = processor.Process(context.Background(), reader, 3)
+	report, err := processor.Process(context.Background(), reader, 3)
 	require.NoError(t, err)
 
 	reportLog := logger.LoggedEntries[len(logger.LoggedEntries)-1]
@@ -61,10 +65,17 @@ func TestGeolocationDataProcessor_Process_all_success(t *testing.T) {
 	}
 
 	assert.Equal(t, map[string]interface{}{
+		"read":              3,
 		"accepted":          3,
 		"discarded":         0,
 		"discarded_reasons": map[string]uint(nil),
 	}, reportLogData)
+
+	assert.Equal(t, 3, report.Read)
+	assert.Equal(t, 3, report.Accepted)
+	assert.Equal(t, 0, report.Discarded)
+	require.Len(t, report.Files, 1)
+	assert.Equal(t, 3, report.Files[0].Accepted)
 }
 
 func TestGeolocationDataProcessor_Process_all_failure(t *testing.T) {
@@ -96,7 +107,7 @@ func TestGeolocationDataProcessor_Process_all_failure(t *testing.T) {
 	processor := NewParseGeolocationData(storage, logger)
 
 	// Process with 3 parallel processes
-	err = processor.Process(context.Background(), reader, 3)
+	report, err := processor.Process(context.Background(), reader, 3)
 	require.NoError(t, err)
 
 	reportLog := logger.LoggedEntries[len(logger.LoggedEntries)-1]
@@ -114,10 +125,16 @@ func TestGeolocationDataProcessor_Process_all_failure(t *testing.T) {
 	}
 
 	assert.Equal(t, map[string]interface{}{
+		"read":              5,
 		"accepted":          0,
 		"discarded":         5,
-		"discarded_reasons": map[string]uint{"not enough fields in input": 0x5},
+		"discarded_reasons": map[string]uint{"invalid_field_count": 0x5},
 	}, reportLogData)
+
+	assert.Equal(t, 5, report.Read)
+	assert.Equal(t, 0, report.Accepted)
+	assert.Equal(t, 5, report.Discarded)
+	assert.Equal(t, map[string]uint{"invalid_field_count": 0x5}, report.DiscardedReasons)
 }
 
 func TestGeolocationDataProcessor_Process(t *testing.T) {
@@ -149,7 +166,7 @@ func TestGeolocationDataProcessor_Process(t *testing.T) {
 	processor := NewParseGeolocationData(storage, logger)
 
 	// Process with 3 parallel processes
-	err = processor.Process(context.Background(), reader, 3)
+	report, err := processor.Process(context.Background(), reader, 3)
 	require.NoError(t, err)
 
 	reportLog := logger.LoggedEntries[len(logger.LoggedEntries)-1]
@@ -167,8 +184,577 @@ func TestGeolocationDataProcessor_Process(t *testing.T) {
 	}
 
 	assert.Equal(t, map[string]interface{}{
+		"read":              5,
 		"accepted":          4,
 		"discarded":         1,
-		"discarded_reasons": map[string]uint{"missing ip address": 0x1},
+		"discarded_reasons": map[string]uint{"missing_ip_address": 0x1},
 	}, reportLogData)
+
+	assert.Equal(t, 5, report.Read)
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, 1, report.Discarded)
+}
+
+func TestGeolocationDataProcessor_Process_ipv6(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadIPv6SampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	var saved []string
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, geos []*model.Geolocation) {
+			for _, geo := range geos {
+				saved = append(saved, geo.IPAddress)
+			}
+		}).
+		Return(nil)
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithDecodeOptions(model.WithMappedIPv4Policy(model.KeepMappedIPv4)),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, report.Read)
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, 2, report.Discarded)
+	assert.Equal(t, map[string]uint{
+		"duplicate_ip_address": 1,
+		"invalid_ip_address":   1,
+	}, report.DiscardedReasons)
+	assert.Equal(t, []string{"2001:db8::1", "::ffff:70.95.73.73", "70.95.73.73", "2001:db8:85a3::8a2e:370:7334"}, saved)
+}
+
+func TestGeolocationDataProcessor_Process_rules(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	// Missing city, only warned.
+	data[1][3] = ""
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).Return(nil)
+
+	rules, err := model.NewRuleSet(model.RuleConfig{Name: model.RuleCity, Severity: model.SeverityWarn})
+	require.NoError(t, err)
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{}, WithRules(rules))
+
+	report, err := processor.Process(context.Background(), reader, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, 1, report.Discarded)
+	assert.Equal(t, map[string]RuleReport{
+		model.RuleIPAddress: {Rejected: 1},
+		model.RuleCity:      {Warned: 1},
+	}, report.Rules)
+}
+
+func TestGeolocationDataProcessor_Process_progress(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.AnythingOfType(reflect.TypeOf([]*model.Geolocation{}).String())).Return(nil)
+
+	var progress []Progress
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithProgress(time.Millisecond, func(_ context.Context, p Progress) {
+			progress = append(progress, p)
+		}),
+	)
+
+	_, err = processor.Process(context.Background(), reader, 3)
+	require.NoError(t, err)
+
+	require.NotEmpty(t, progress)
+
+	last := progress[len(progress)-1]
+
+	assert.True(t, last.Done)
+	assert.Equal(t, 5, last.Read)
+	assert.Equal(t, 4, last.Accepted)
+	assert.Equal(t, 1, last.Discarded)
+}
+
+func TestGeolocationDataProcessor_Process_batch_size(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage, 4 valid geolocation data saved in batches of 2 by a single worker.
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.MatchedBy(func(geos []*model.Geolocation) bool {
+		return len(geos) == 2
+	})).Return(nil).Twice()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithBatchSize(2),
+		WithSaverWorkers(1),
+		WithReadyBuffer(1),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, 1, report.Discarded)
+}
+
+func TestGeolocationDataProcessor_Process_last_wins(t *testing.T) {
+	t.Parallel()
+
+	data := [][]string{
+		{"200.106.141.15", "SI", "Nepal", "DuBuquemouth", "-84.87503094689836", "7.206435933364332", "7823011346"},
+		{"160.103.7.140", "CZ", "Nicaragua", "New Neva", "-68.31023296602508", "-37.62435199624531", "7301823115"},
+		{"200.106.141.15", "TL", "Saudi Arabia", "Gradymouth", "-49.16675918861615", "-86.05920084416894", "2559997162"},
+	}
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	var saved []*model.Geolocation
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, geos []*model.Geolocation) {
+			saved = append(saved, geos...)
+		}).
+		Return(nil)
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithDuplicatePolicy(LastWins),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, report.Accepted)
+	assert.Equal(t, 1, report.Discarded)
+	assert.Equal(t, map[string]uint{string(model.CodeDuplicateIPAddress): 1}, report.DiscardedReasons)
+
+	require.Len(t, saved, 2)
+
+	for _, geo := range saved {
+		if geo.IPAddress == "200.106.141.15" {
+			assert.Equal(t, "Gradymouth", geo.City)
+		}
+	}
+}
+
+func TestGeolocationDataProcessor_Process_duplication_spill(t *testing.T) {
+	t.Parallel()
+
+	var data [][]string
+
+	for i := range 300 {
+		data = append(data, []string{fmt.Sprintf("10.0.%d.%d", i/256, i%256), "SI", "Nepal", "DuBuquemouth", "-84.87", "7.20", "7823011346"})
+	}
+
+	// The first IP addresses are repeated once the others are spilled.
+	data = append(data, data[:10]...)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).Return(nil)
+
+	dir := t.TempDir()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithDuplicationSpill(dir, duplicationShards),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, 300, report.Accepted)
+	assert.Equal(t, map[string]uint{string(model.CodeDuplicateIPAddress): 10}, report.DiscardedReasons)
+
+	// The spilled IP addresses are removed.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestGeolocationDataProcessor_Process_incremental(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// Stored: 200.106.141.15 unchanged, 160.103.7.140 changed.
+	unchanged, err := model.DecodeGeolocation(data[0])
+	require.NoError(t, err)
+
+	changed, err := model.DecodeGeolocation(data[1])
+	require.NoError(t, err)
+
+	changed.City = "Old Neva"
+
+	merger := mocks.NewGeolocationDataMerger(t)
+	merger.EXPECT().FindGeolocationsByIP(mock.Anything, mock.Anything).Return(map[string]model.Geolocation{
+		unchanged.IPAddress: unchanged,
+		changed.IPAddress:   changed,
+	}, nil)
+	merger.EXPECT().MergeGeolocation(mock.Anything,
+		mock.MatchedBy(func(geos []*model.Geolocation) bool {
+			return len(geos) == 2
+		}),
+		mock.MatchedBy(func(geos []*model.Geolocation) bool {
+			return len(geos) == 1 && geos[0].IPAddress == changed.IPAddress && geos[0].City == "New Neva"
+		}),
+	).Return(nil)
+
+	processor := NewParseGeolocationData(mocks.NewGeolocationDataStorage(t), &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithIncremental(merger),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, 1, report.Discarded)
+	assert.Equal(t, &MergeReport{New: 2, Changed: 1, Unchanged: 1}, report.Merge)
+}
+
+// headerReader is a reader providing the header of the geolocation data.
+type headerReader struct {
+	*mocks.GeolocationDataReader
+
+	header []string
+}
+
+func (r headerReader) Header() []string {
+	return r.header
+}
+
+func TestGeolocationDataProcessor_Process_columns(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadExtendedSampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data[1:] {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	var saved []model.Geolocation
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, geos []*model.Geolocation) {
+			for _, geo := range geos {
+				saved = append(saved, *geo)
+			}
+		}).
+		Return(nil)
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{}, WithSaverWorkers(1))
+
+	report, err := processor.Process(context.Background(), headerReader{GeolocationDataReader: reader, header: data[0]}, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Read)
+	assert.Equal(t, 3, report.Accepted)
+	assert.Equal(t, map[string]uint{
+		"invalid_time_zone": 1,
+	}, report.DiscardedReasons)
+
+	require.Len(t, saved, 3)
+	assert.Equal(t, "Europe/Ljubljana", saved[0].TimeZone)
+	assert.Equal(t, uint32(2108), saved[0].ASN)
+	assert.Equal(t, "Telekom Slovenije", saved[0].Organization)
+	assert.Equal(t, model.Attributes{"connection_type": "cable"}, saved[0].Attributes)
+	assert.Equal(t, uint32(100), saved[2].AccuracyRadius)
+}
+
+// sourceReader is a headerReader naming its source.
+type sourceReader struct {
+	headerReader
+
+	name string
+}
+
+func (r sourceReader) SourceInfo() SourceInfo {
+	return SourceInfo{Name: r.name}
+}
+
+func TestGeolocationDataProcessor_Process_provenance(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadExtendedSampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data[1:] {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	provenance := make(map[string]string)
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, geos []*model.Geolocation) {
+			for _, geo := range geos {
+				provenance[geo.IPAddress] = fmt.Sprintf("%d %s:%d", geo.VersionID, geo.SourceFile, geo.SourceLine)
+			}
+		}).
+		Return(nil)
+
+	versioner := mocks.NewGeolocationDataVersioner(t)
+	versioner.EXPECT().CreateDatasetVersion(mock.Anything, "data_dump.csv").Return(42, nil).Once()
+	versioner.EXPECT().CompleteDatasetVersion(mock.Anything, int64(42), 3).Return(nil).Once()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithVersioning(versioner),
+	)
+
+	_, err = processor.Process(context.Background(), sourceReader{
+		headerReader: headerReader{GeolocationDataReader: reader, header: data[0]},
+		name:         "data_dump.csv",
+	}, 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{
+		"200.106.141.15": "42 data_dump.csv:2",
+		"160.103.7.140":  "42 data_dump.csv:3",
+		"70.95.73.73":    "42 data_dump.csv:4",
+	}, provenance)
+}
+
+func TestGeolocationDataProcessor_Process_missing_column(t *testing.T) {
+	t.Parallel()
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Return(make(chan []string), nil)
+
+	processor := NewParseGeolocationData(mocks.NewGeolocationDataStorage(t), &ctxd.LoggerMock{})
+
+	_, err := processor.Process(context.Background(), headerReader{
+		GeolocationDataReader: reader,
+		header:                []string{"ip_address", "country_code", "country", "city", "latitude", "longitude", "asn", "org"},
+	}, 1)
+	require.ErrorContains(t, err, "missing column")
+}
+
+func TestGeolocationDataProcessor_Process_versioning(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	// reader
+	dataCh := make(chan []string, len(data))
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).Run(func(_ context.Context) {
+		go func() {
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+		}()
+	}).Return(dataCh, nil)
+
+	// storage
+	var versions []int64
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, geos []*model.Geolocation) {
+			for _, geo := range geos {
+				versions = append(versions, geo.VersionID)
+			}
+		}).
+		Return(nil)
+
+	versioner := mocks.NewGeolocationDataVersioner(t)
+	versioner.EXPECT().CreateDatasetVersion(mock.Anything, "").Return(42, nil).Once()
+	versioner.EXPECT().CompleteDatasetVersion(mock.Anything, int64(42), 4).Return(nil).Once()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithVersioning(versioner),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), report.Version)
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, []int64{42, 42, 42, 42}, versions)
+}
+
+func TestGeolocationDataProcessor_Process_versioning_failure(t *testing.T) {
+	t.Parallel()
+
+	var readCtx context.Context
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).
+		Run(func(ctx context.Context) { readCtx = ctx }).
+		Return(make(chan []string), nil)
+
+	versioner := mocks.NewGeolocationDataVersioner(t)
+	versioner.EXPECT().CreateDatasetVersion(mock.Anything, "").Return(0, errors.New("connection refused")).Once()
+
+	processor := NewParseGeolocationData(mocks.NewGeolocationDataStorage(t), &ctxd.LoggerMock{},
+		WithVersioning(versioner),
+	)
+
+	_, err := processor.Process(context.Background(), reader, 1)
+	require.ErrorContains(t, err, "creating the dataset version")
+
+	// The reader is stopped.
+	require.ErrorIs(t, readCtx.Err(), context.Canceled)
+}
+
+func TestGeolocationDataProcessor_Process_canceled(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		Run(func(_ context.Context, _ []*model.Geolocation) { cancel() }).
+		Return(context.Canceled).Maybe()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{}, WithBatchSize(1))
+
+	_, err := processor.Process(ctx, sampleReader(t), 1)
+	require.ErrorIs(t, err, context.Canceled)
 }
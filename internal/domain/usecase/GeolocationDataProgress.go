@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Progress is a snapshot of the progress of processing geolocation data.
+type Progress struct {
+	Read      int
+	Accepted  int
+	Discarded int
+
+	Elapsed time.Duration
+	// Rate is the number of records read per second.
+	Rate float64
+
+	// BytesConsumed and BytesTotal are only known when the reader implements GeolocationDataSource.
+	BytesConsumed int64
+	BytesTotal    int64
+	// ETA is the estimated remaining time based on the bytes consumed. Zero when unknown.
+	ETA time.Duration
+
+	// Done is true for the last snapshot, once the processing finished.
+	Done bool
+}
+
+// ProgressFunc is called periodically with the progress of processing geolocation data.
+type ProgressFunc func(ctx context.Context, p Progress)
+
+// progressTracker periodically reports the progress of the processing until stopped.
+type progressTracker struct {
+	fn       ProgressFunc
+	interval time.Duration
+
+	r         *reporter
+	reader    GeolocationDataReader
+	startTime time.Time
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func (t *progressTracker) start(ctx context.Context) {
+	t.done = make(chan struct{})
+
+	t.wg.Add(1)
+
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.done:
+				return
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				t.fn(ctx, t.snapshot(false))
+			}
+		}
+	}()
+}
+
+// stop stops the periodic reporting and reports the final progress.
+func (t *progressTracker) stop(ctx context.Context) {
+	close(t.done)
+
+	t.wg.Wait()
+
+	t.fn(ctx, t.snapshot(true))
+}
+
+func (t *progressTracker) snapshot(done bool) Progress {
+	read, accepted, discarded := t.r.counts()
+
+	p := Progress{
+		Read:      read,
+		Accepted:  accepted,
+		Discarded: discarded,
+		Elapsed:   time.Since(t.startTime),
+		Done:      done,
+	}
+
+	if p.Elapsed > 0 {
+		p.Rate = float64(read) / p.Elapsed.Seconds()
+	}
+
+	src, ok := t.reader.(GeolocationDataSource)
+	if !ok {
+		return p
+	}
+
+	info := src.SourceInfo()
+
+	p.BytesConsumed = info.Consumed
+	p.BytesTotal = info.Size
+
+	if !done && info.Consumed > 0 && info.Size > info.Consumed {
+		p.ETA = time.Duration(float64(p.Elapsed) * float64(info.Size-info.Consumed) / float64(info.Consumed))
+	}
+
+	return p
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"time"
+)
+
+// GeolocationDataSource is the interface a GeolocationDataReader can optionally implement to describe the source
+// the geolocation data is read from.
+type GeolocationDataSource interface {
+	SourceInfo() SourceInfo
+}
+
+// SourceInfo describes the source of the geolocation data.
+type SourceInfo struct {
+	// Name is the name of the source, i.e. the file path.
+	Name string
+	// Size is the size of the source in bytes. Zero when unknown.
+	Size int64
+	// Consumed is the number of bytes consumed from the source so far.
+	Consumed int64
+}
+
+// Report summarizes the result of processing geolocation data.
+type Report struct {
+	Read             int             `json:"read" yaml:"read"`
+	Accepted         int             `json:"accepted" yaml:"accepted"`
+	Discarded        int             `json:"discarded" yaml:"discarded"`
+	DiscardedReasons map[string]uint `json:"discarded_reasons" yaml:"discarded_reasons"`
+	// Version is the dataset version created by the processing, zero when versioning is disabled.
+	Version int64 `json:"version,omitempty" yaml:"version,omitempty"`
+	// Rules counts the violations by validation rule.
+	Rules map[string]RuleReport `json:"rules,omitempty" yaml:"rules,omitempty"`
+
+	StartedAt       time.Time `json:"started_at" yaml:"started_at"`
+	DurationSeconds float64   `json:"duration_s" yaml:"duration_s"`
+	// Throughput is the number of records read per second.
+	Throughput float64 `json:"throughput_rps" yaml:"throughput_rps"`
+
+	Files []FileReport `json:"files" yaml:"files"`
+
+	// Merge is only reported in incremental and sync mode.
+	Merge *MergeReport `json:"merge,omitempty" yaml:"merge,omitempty"`
+	// Sync is only reported in sync mode.
+	Sync *SyncReport `json:"sync,omitempty" yaml:"sync,omitempty"`
+}
+
+// RuleReport counts the geolocation data violating a validation rule.
+type RuleReport struct {
+	Rejected uint `json:"rejected" yaml:"rejected"`
+	Warned   uint `json:"warned" yaml:"warned"`
+}
+
+// MergeReport summarizes how the geolocation data accepted compares to the stored one.
+type MergeReport struct {
+	New       int `json:"new" yaml:"new"`
+	Changed   int `json:"changed" yaml:"changed"`
+	Unchanged int `json:"unchanged" yaml:"unchanged"`
+}
+
+// FileReport summarizes the result of processing geolocation data from a single source.
+type FileReport struct {
+	Name      string `json:"name" yaml:"name"`
+	SizeBytes int64  `json:"size_bytes" yaml:"size_bytes"`
+	Read      int    `json:"read" yaml:"read"`
+	Accepted  int    `json:"accepted" yaml:"accepted"`
+	Discarded int    `json:"discarded" yaml:"discarded"`
+}
+
+// newReport builds the Report out of the reporter counters.
+func newReport(r *reporter, reader GeolocationDataReader, startTime time.Time, duration time.Duration) Report {
+	report := Report{
+		Read:             r.read,
+		Accepted:         r.accepted,
+		Discarded:        r.discarded,
+		DiscardedReasons: r.discardedReasons,
+		Rules:            r.rules,
+		StartedAt:        startTime,
+		DurationSeconds:  duration.Seconds(),
+	}
+
+	if duration > 0 {
+		report.Throughput = float64(r.read) / duration.Seconds()
+	}
+
+	file := FileReport{
+		Read:      r.read,
+		Accepted:  r.accepted,
+		Discarded: r.discarded,
+	}
+
+	if src, ok := reader.(GeolocationDataSource); ok {
+		info := src.SourceInfo()
+
+		file.Name = info.Name
+		file.SizeBytes = info.Size
+	}
+
+	report.Files = []FileReport{file}
+
+	return report
+}
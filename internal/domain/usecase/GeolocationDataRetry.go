@@ -0,0 +1,119 @@
+package usecase
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"github.com/dohernandez/vio/internal/domain/model"
+)
+
+const (
+	// defaultRetryBackoff is the default wait before the first retry of a batch.
+	defaultRetryBackoff = 100 * time.Millisecond
+	// defaultMaxRetryBackoff is the default upper bound of the wait between retries of a batch.
+	defaultMaxRetryBackoff = 5 * time.Second
+)
+
+// RetryOptions configures the retries of the batches failing to be saved.
+type RetryOptions struct {
+	// Retries is the number of times a batch failing with a retryable error is saved again.
+	Retries int
+	// Backoff is the wait before the first retry, doubled on each retry up to MaxBackoff, with jitter. Defaults to
+	// 100ms.
+	Backoff time.Duration
+	// MaxBackoff is the upper bound of the wait between retries. Defaults to 5s.
+	MaxBackoff time.Duration
+	// Retryable tells whether the error is transient, i.e. a serialization failure or a connection loss.
+	Retryable func(err error) bool
+	// DataError tells whether the error is caused by the geolocation data, i.e. a constraint violation.
+	DataError func(err error) bool
+}
+
+// WithRetry retries the batches failing to be saved with a retryable error, with exponential backoff.
+//
+// A batch failing because of its geolocation data is saved again row by row, to isolate the geolocation data at fault,
+// the rows are retried as the batches. A batch failing with any other error, or still failing with a retryable one
+// once out of retries, fails as a whole.
+//
+// A batch saved despite failing, i.e. when the connection is lost on commit, is saved again by the retry. The storage
+// keeps a single geolocation data per IP address in a dataset, leaving the one already stored as it is, so the batch is
+// not stored twice.
+func WithRetry(opts RetryOptions) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		if opts.Backoff <= 0 {
+			opts.Backoff = defaultRetryBackoff
+		}
+
+		if opts.MaxBackoff <= 0 {
+			opts.MaxBackoff = defaultMaxRetryBackoff
+		}
+
+		p.retry = opts
+	}
+}
+
+// writeRetrying writes the batch of geolocation data, retrying it while it fails with a retryable error.
+func (p *GeolocationDataProcessor) writeRetrying(ctx context.Context, buf []*model.Geolocation, r *reporter) error {
+	backoff := p.retry.Backoff
+
+	for attempt := 0; ; attempt++ {
+		err := p.write(ctx, buf, r)
+		if err == nil || attempt >= p.retry.Retries || !p.retryable(err) {
+			return err
+		}
+
+		// Full jitter, the saver workers failing at once do not retry at once.
+		wait := rand.N(backoff) + 1 //nolint:gosec // Jitter does not need a secure random.
+
+		p.logger.Debug(ctx, "retrying to save geolocation data",
+			"error", err,
+			"attempt", attempt+1,
+			"wait", wait.String(),
+		)
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+
+			return err
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, p.retry.MaxBackoff)
+	}
+}
+
+// writeRows writes the geolocation data of the batch which failed to be saved one by one, reporting each one saved or
+// failed.
+func (p *GeolocationDataProcessor) writeRows(ctx context.Context, buf []*model.Geolocation, r *reporter) {
+	for i, geo := range buf {
+		if ctx.Err() != nil {
+			r.storageFailed(len(buf) - i)
+
+			return
+		}
+
+		if err := p.writeRetrying(ctx, []*model.Geolocation{geo}, r); err != nil {
+			r.storageFailed(1)
+
+			p.logger.Debug(ctx, "save geolocation data", "error", err, "ip_address", geo.IPAddress)
+
+			continue
+		}
+
+		r.succeed(1)
+	}
+}
+
+// retryable tells whether the error is transient, none is without WithRetry.
+func (p *GeolocationDataProcessor) retryable(err error) bool {
+	return p.retry.Retryable != nil && p.retry.Retryable(err)
+}
+
+// dataError tells whether the error is caused by the geolocation data, none is without WithRetry.
+func (p *GeolocationDataProcessor) dataError(err error) bool {
+	return p.retry.DataError != nil && p.retry.DataError(err)
+}
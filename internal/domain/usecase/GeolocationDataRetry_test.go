@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/domain/usecase/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	errConnectionReset = errors.New("connection reset by peer")
+	errInvalidInput    = errors.New("invalid input syntax")
+	errDiskFull        = errors.New("could not extend file")
+)
+
+func retryOptions(retries int) RetryOptions {
+	return RetryOptions{
+		Retries:    retries,
+		Backoff:    time.Millisecond,
+		MaxBackoff: 2 * time.Millisecond,
+		Retryable: func(err error) bool {
+			return errors.Is(err, errConnectionReset)
+		},
+		DataError: func(err error) bool {
+			return errors.Is(err, errInvalidInput)
+		},
+	}
+}
+
+func TestGeolocationDataProcessor_Process_retry(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).Return(errConnectionReset).Twice()
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).Return(nil).Once()
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithRetry(retryOptions(2)),
+	)
+
+	report, err := processor.Process(context.Background(), sampleReader(t), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Accepted)
+	assert.NotContains(t, report.DiscardedReasons, DiscardReasonStorage)
+}
+
+func TestGeolocationDataProcessor_Process_retry_exhausted(t *testing.T) {
+	t.Parallel()
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).Return(errConnectionReset).Times(3)
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithRetry(retryOptions(2)),
+	)
+
+	report, err := processor.Process(context.Background(), sampleReader(t), 1)
+	require.NoError(t, err)
+
+	// The batch is not saved row by row while the storage is unavailable, each of its rows is discarded.
+	assert.Equal(t, 0, report.Accepted)
+	assert.Equal(t, uint(4), report.DiscardedReasons[DiscardReasonStorage])
+	assert.Equal(t, report.Read, report.Accepted+report.Discarded)
+}
+
+func TestGeolocationDataProcessor_Process_row_by_row(t *testing.T) {
+	t.Parallel()
+
+	const badIP = "160.103.7.140"
+
+	var (
+		mu    sync.Mutex
+		saved []string
+	)
+
+	storage := mocks.NewGeolocationDataStorage(t)
+	storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, geos []*model.Geolocation) error {
+			for _, geo := range geos {
+				if geo.IPAddress == badIP {
+					return errInvalidInput
+				}
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			for _, geo := range geos {
+				saved = append(saved, geo.IPAddress)
+			}
+
+			return nil
+		})
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithRetry(retryOptions(2)),
+	)
+
+	report, err := processor.Process(context.Background(), sampleReader(t), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Accepted)
+	assert.Equal(t, uint(1), report.DiscardedReasons[DiscardReasonStorage])
+	assert.Equal(t, report.Read, report.Accepted+report.Discarded)
+	assert.NotContains(t, saved, badIP)
+	assert.Len(t, saved, 3)
+}
+
+func TestGeolocationDataProcessor_Process_unknown_failure(t *testing.T) {
+	t.Parallel()
+
+	for name, opts := range map[string][]ProcessorOption{
+		"retry":    {WithRetry(retryOptions(2))},
+		"no retry": nil,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			// Neither retried nor saved row by row.
+			storage := mocks.NewGeolocationDataStorage(t)
+			storage.EXPECT().SaveGeolocation(mock.Anything, mock.Anything).Return(errDiskFull).Once()
+
+			processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{}, append(opts, WithSaverWorkers(1))...)
+
+			report, err := processor.Process(context.Background(), sampleReader(t), 1)
+			require.NoError(t, err)
+
+			assert.Equal(t, 0, report.Accepted)
+			assert.Equal(t, uint(4), report.DiscardedReasons[DiscardReasonStorage])
+		})
+	}
+}
@@ -0,0 +1,254 @@
+package usecase
+
+import (
+	"bytes"
+	"hash/maphash"
+	"os"
+	"slices"
+	"sync"
+)
+
+// spillRecordSize is the size of an ipKey spilled to disk: the 16 bytes of the address and whether it is IPv4.
+const spillRecordSize = 17
+
+// The bloom filter of a spilled run is sized for about 1% of false positives.
+const (
+	bloomBitsPerKey = 10
+	bloomHashes     = 7
+)
+
+// spillOptions sets up the spilling of the IP addresses of duplication to disk.
+type spillOptions struct {
+	// dir is the directory of the temporary file, the default directory for temporary files when empty.
+	dir string
+	// maxKeys is the number of IP addresses held in memory, past which they are spilled. Zero disables spilling.
+	maxKeys int
+}
+
+// record returns the representation of the key spilled to disk, ordered as the keys are in a run.
+func (k ipKey) record() [spillRecordSize]byte {
+	var rec [spillRecordSize]byte
+
+	copy(rec[:], k.addr[:])
+
+	if k.is4 {
+		rec[16] = 1
+	}
+
+	return rec
+}
+
+// spillFile is the temporary file the IP addresses are spilled to, shared by the shards of duplication. It is created
+// on the first spill.
+type spillFile struct {
+	dir  string
+	f    *os.File
+	size int64
+
+	mu sync.Mutex
+}
+
+// write appends the records to the file, returning their offset.
+func (s *spillFile) write(buf []byte) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		f, err := os.CreateTemp(s.dir, "vio-duplication-*")
+		if err != nil {
+			return 0, err
+		}
+
+		s.f = f
+	}
+
+	offset := s.size
+
+	if _, err := s.f.WriteAt(buf, offset); err != nil {
+		return 0, err
+	}
+
+	s.size += int64(len(buf))
+
+	return offset, nil
+}
+
+// close removes the file.
+func (s *spillFile) close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.f == nil {
+		return nil
+	}
+
+	f := s.f
+	s.f = nil
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(f.Name())
+}
+
+// bloomFilter tells whether a record may be in a spilled run, false positives included.
+type bloomFilter struct {
+	bits []uint64
+	seed maphash.Seed
+}
+
+func newBloomFilter(n int, seed maphash.Seed) bloomFilter {
+	return bloomFilter{
+		bits: make([]uint64, (n*bloomBitsPerKey+63)/64),
+		seed: seed,
+	}
+}
+
+// positions calls fn with the bits of the record, derived from a single hash by double hashing.
+func (b bloomFilter) positions(rec []byte, fn func(i uint64) bool) {
+	h := maphash.Bytes(b.seed, rec)
+	h1, h2 := h&0xffffffff, h>>32|1
+	m := uint64(len(b.bits)) * 64
+
+	for i := range uint64(bloomHashes) {
+		if !fn((h1 + i*h2) % m) {
+			return
+		}
+	}
+}
+
+func (b bloomFilter) add(rec []byte) {
+	b.positions(rec, func(i uint64) bool {
+		b.bits[i/64] |= 1 << (i % 64)
+
+		return true
+	})
+}
+
+func (b bloomFilter) mayContain(rec []byte) bool {
+	found := true
+
+	b.positions(rec, func(i uint64) bool {
+		found = b.bits[i/64]&(1<<(i%64)) != 0
+
+		return found
+	})
+
+	return found
+}
+
+// spillRun is a set of IP addresses spilled to disk, sorted so that they are found by binary search.
+type spillRun struct {
+	offset int64
+	n      int
+	filter bloomFilter
+}
+
+func (r spillRun) contains(file *spillFile, rec []byte) (bool, error) {
+	if !r.filter.mayContain(rec) {
+		return false, nil
+	}
+
+	var buf [spillRecordSize]byte
+
+	lo, hi := 0, r.n
+
+	for lo < hi {
+		mid := int(uint(lo+hi) >> 1)
+
+		if _, err := file.f.ReadAt(buf[:], r.offset+int64(mid)*spillRecordSize); err != nil {
+			return false, err
+		}
+
+		switch c := bytes.Compare(buf[:], rec); {
+		case c == 0:
+			return true, nil
+		case c < 0:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return false, nil
+}
+
+// ipSet is a set of IP addresses held in memory, spilled to disk in sorted runs once the maximum of keys is reached.
+type ipSet struct {
+	keys map[ipKey]struct{}
+	runs []spillRun
+}
+
+// contains checks whether the IP address is in the set, in memory or in a spilled run.
+func (s *ipSet) contains(k ipKey, file *spillFile) (bool, error) {
+	if _, ok := s.keys[k]; ok {
+		return true, nil
+	}
+
+	if len(s.runs) == 0 {
+		return false, nil
+	}
+
+	rec := k.record()
+
+	for _, r := range s.runs {
+		ok, err := r.contains(file, rec[:])
+		if err != nil || ok {
+			return ok, err
+		}
+	}
+
+	return false, nil
+}
+
+// add adds the IP address not in the set, the keys held in memory are spilled first when maxKeys are held already.
+// The IP address is not added when the spill fails.
+func (s *ipSet) add(k ipKey, file *spillFile, maxKeys int, seed maphash.Seed) error {
+	if s.keys == nil {
+		s.keys = make(map[ipKey]struct{})
+	}
+
+	if maxKeys > 0 && len(s.keys) >= maxKeys {
+		if err := s.spill(file, seed); err != nil {
+			return err
+		}
+	}
+
+	s.keys[k] = struct{}{}
+
+	return nil
+}
+
+// spill writes the keys held in memory to the file as a new run, releasing them.
+func (s *ipSet) spill(file *spillFile, seed maphash.Seed) error {
+	recs := make([][spillRecordSize]byte, 0, len(s.keys))
+
+	for k := range s.keys {
+		recs = append(recs, k.record())
+	}
+
+	slices.SortFunc(recs, func(a, b [spillRecordSize]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+
+	filter := newBloomFilter(len(recs), seed)
+	buf := make([]byte, 0, len(recs)*spillRecordSize)
+
+	for _, rec := range recs {
+		filter.add(rec[:])
+
+		buf = append(buf, rec[:]...)
+	}
+
+	offset, err := file.write(buf)
+	if err != nil {
+		return err
+	}
+
+	s.runs = append(s.runs, spillRun{offset: offset, n: len(recs), filter: filter})
+
+	clear(s.keys)
+
+	return nil
+}
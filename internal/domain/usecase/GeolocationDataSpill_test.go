@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDuplication_check_spilled(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	// A single IP address per shard is held in memory, the others are spilled.
+	dupl := newDuplication(FirstWins, model.UnmapIPv4, spillOptions{dir: dir, maxKeys: duplicationShards})
+
+	ips := make([]string, 0, 1000)
+
+	for i := range cap(ips) {
+		ips = append(ips, fmt.Sprintf("10.0.%d.%d", i/256, i%256))
+	}
+
+	for i, ip := range ips {
+		isReady, err := dupl.check(&model.Geolocation{IPAddress: ip}, uint64(i))
+		require.NoError(t, err)
+		require.True(t, isReady, ip)
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+
+	for i, ip := range ips {
+		isReady, err := dupl.check(&model.Geolocation{IPAddress: ip}, uint64(len(ips)+i))
+		require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists, ip)
+		require.False(t, isReady, ip)
+	}
+
+	// The IP addresses are canonicalised before being spilled.
+	_, err = dupl.check(&model.Geolocation{IPAddress: "::ffff:10.0.0.1"}, uint64(2*len(ips)))
+	require.ErrorIs(t, err, model.ErrGeolocationAlreadyExists)
+
+	found, err := dupl.contains("10.0.3.231")
+	require.NoError(t, err)
+	require.True(t, found)
+
+	found, err = dupl.contains("10.0.3.232")
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, dupl.close())
+
+	entries, err = os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestDuplication_keep_spilled(t *testing.T) {
+	t.Parallel()
+
+	dupl := newDuplication(FirstWins, model.UnmapIPv4, spillOptions{dir: t.TempDir(), maxKeys: duplicationShards})
+
+	defer dupl.close() //nolint:errcheck
+
+	for i := range 500 {
+		require.NoError(t, dupl.keep(fmt.Sprintf("2001:db8::%x", i)))
+	}
+
+	// Kept again, an IP address already spilled is not spilled twice.
+	require.NoError(t, dupl.keep("2001:db8::1"))
+	require.NoError(t, dupl.keep("invalid"))
+
+	for i := range 500 {
+		found, err := dupl.contains(fmt.Sprintf("2001:DB8:0::%x", i))
+		require.NoError(t, err)
+		require.True(t, found)
+	}
+
+	found, err := dupl.contains("2001:db8::1:0")
+	require.NoError(t, err)
+	require.False(t, found)
+}
+
+func TestDuplication_check_spill_failure(t *testing.T) {
+	t.Parallel()
+
+	// The spill fails, the directory does not exist.
+	dupl := newDuplication(FirstWins, model.UnmapIPv4, spillOptions{dir: t.TempDir() + "/missing", maxKeys: 1})
+
+	var failed *model.Geolocation
+
+	for i := range 1000 {
+		geo := &model.Geolocation{IPAddress: fmt.Sprintf("10.0.%d.%d", i/256, i%256)}
+
+		if _, err := dupl.check(geo, uint64(i)); err != nil {
+			require.ErrorContains(t, err, "spilling ip addresses")
+
+			failed = geo
+
+			break
+		}
+	}
+
+	require.NotNil(t, failed)
+
+	// The IP address failing to be spilled is not recorded, it is not a duplicate once checked again.
+	found, err := dupl.contains(failed.IPAddress)
+	require.NoError(t, err)
+	require.False(t, found)
+
+	require.NoError(t, dupl.close())
+}
+
+func TestBloomFilter(t *testing.T) {
+	t.Parallel()
+
+	dupl := newDuplication(FirstWins, model.UnmapIPv4, spillOptions{})
+	filter := newBloomFilter(1000, dupl.seed)
+
+	for i := range 1000 {
+		rec := ipKey{addr: [16]byte{0, byte(i >> 8), byte(i)}}.record()
+
+		filter.add(rec[:])
+	}
+
+	falsePositives := 0
+
+	for i := range 2000 {
+		rec := ipKey{addr: [16]byte{0, byte(i >> 8), byte(i)}}.record()
+
+		if i < 1000 {
+			require.True(t, filter.mayContain(rec[:]), i)
+
+			continue
+		}
+
+		if filter.mayContain(rec[:]) {
+			falsePositives++
+		}
+	}
+
+	require.Less(t, falsePositives, 50)
+}
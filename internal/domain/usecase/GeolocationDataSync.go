@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+)
+
+//go:generate mockery --name=GeolocationDataSyncer --outpkg=mocks --output=mocks --filename=geolocation_data_syncer.go --with-expecter
+
+// GeolocationDataSyncer is the interface that provides the ability to remove the stored geolocation data no longer
+// present in the source.
+type GeolocationDataSyncer interface {
+	GeolocationDataLister
+
+	// DeleteGeolocation deletes the stored geolocation data of the given IPs, all of them or none.
+	DeleteGeolocation(ctx context.Context, ips []string) error
+	// SoftDeleteGeolocation marks the stored geolocation data of the given IPs as deleted, all of them or none.
+	SoftDeleteGeolocation(ctx context.Context, ips []string) error
+}
+
+// SyncOptions configures the sync mode.
+type SyncOptions struct {
+	// SoftDelete marks the stale geolocation data as deleted instead of deleting it.
+	SoftDelete bool
+	// MaxDeletionPercent is the maximum percentage of the stored geolocation data allowed to be deleted.
+	// The sync fails without writing anything when exceeded.
+	MaxDeletionPercent float64
+}
+
+// SyncReport summarizes the stale geolocation data removed in sync mode.
+type SyncReport struct {
+	Stored      int  `json:"stored" yaml:"stored"`
+	Stale       int  `json:"stale" yaml:"stale"`
+	SoftDeleted bool `json:"soft_deleted" yaml:"soft_deleted"`
+}
+
+// ErrTooManyDeletions is returned when the sync would delete more geolocation data than allowed.
+var ErrTooManyDeletions = errors.New("too many geolocation data to delete")
+
+// staleGeolocation returns the stored geolocation data which IP address is not found in the source, the source being
+// read ahead of the load. A row of the source rejected keeps its stored geolocation data.
+//
+// Fails with ErrTooManyDeletions when more than MaxDeletionPercent of the stored geolocation data is stale, before
+// anything is written.
+func (p *GeolocationDataProcessor) staleGeolocation(ctx context.Context, reader GeolocationDataReader) ([]string, SyncReport, error) {
+	report := SyncReport{
+		SoftDeleted: p.syncOpts.SoftDelete,
+	}
+
+	found, err := p.scan(ctx, reader)
+	if err != nil {
+		return nil, report, err
+	}
+
+	defer p.closeDuplication(ctx, found)
+
+	var stale []string
+
+	err = p.syncer.ListGeolocation(ctx, func(geo model.Geolocation) error {
+		report.Stored++
+
+		ok, err := found.contains(geo.IPAddress)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			stale = append(stale, geo.IPAddress)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, report, ctxd.WrapError(ctx, err, "listing stored geolocation data")
+	}
+
+	report.Stale = len(stale)
+
+	if len(stale) == 0 {
+		return nil, report, nil
+	}
+
+	if percent := float64(len(stale)) * 100 / float64(report.Stored); percent > p.syncOpts.MaxDeletionPercent {
+		return nil, report, ctxd.WrapError(ctx, ErrTooManyDeletions, "sync aborted",
+			"stale", len(stale),
+			"stored", report.Stored,
+			"stale_percent", percent,
+			"max_deletion_percent", p.syncOpts.MaxDeletionPercent,
+		)
+	}
+
+	return stale, report, nil
+}
+
+// scan reads the source, returning the IP addresses found in it, of the rows rejected as well.
+func (p *GeolocationDataProcessor) scan(ctx context.Context, reader GeolocationDataReader) (*duplication, error) {
+	scanCtx, stopReading := context.WithCancel(ctx)
+	defer stopReading()
+
+	data, err := reader.ReadGeolocationData(scanCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	decodeOpts, err := sourceDecodeOptions(reader, p.decodeOpts)
+	if err != nil {
+		return nil, ctxd.WrapError(ctx, err, "mapping the columns of the geolocation data")
+	}
+
+	found := newDuplication(FirstWins, model.DecodeMappedIPv4Policy(decodeOpts...), p.spill)
+
+	for d := range data {
+		// The IP address is decoded first, the one of a row failing to be decoded is kept.
+		geo, _ := model.DecodeGeolocation(d, decodeOpts...) //nolint:errcheck,contextcheck
+
+		if geo.IPAddress == "" {
+			continue
+		}
+
+		if err := found.keep(geo.IPAddress); err != nil { //nolint:contextcheck
+			p.closeDuplication(ctx, found)
+
+			return nil, ctxd.WrapError(ctx, err, "finding the ip addresses of the source")
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		p.closeDuplication(ctx, found)
+
+		return nil, ctxd.WrapError(ctx, err, "reading geolocation data canceled")
+	}
+
+	return found, nil
+}
+
+// prune removes the stale geolocation data, all of it or none.
+func (p *GeolocationDataProcessor) prune(ctx context.Context, stale []string) error {
+	if len(stale) == 0 {
+		return nil
+	}
+
+	del := p.syncer.DeleteGeolocation
+	if p.syncOpts.SoftDelete {
+		del = p.syncer.SoftDeleteGeolocation
+	}
+
+	if err := del(ctx, stale); err != nil {
+		return ctxd.WrapError(ctx, err, "deleting stale geolocation data")
+	}
+
+	return nil
+}
@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/domain/usecase/mocks"
+	"github.com/dohernandez/vio/internal/platform/helpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// syncReader returns the reader of the data, read once to find the stale geolocation data and once to load it.
+func syncReader(t *testing.T, data [][]string) *mocks.GeolocationDataReader {
+	t.Helper()
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).
+		RunAndReturn(func(_ context.Context) (<-chan []string, error) {
+			dataCh := make(chan []string, len(data))
+
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+
+			return dataCh, nil
+		}).
+		Times(2)
+
+	return reader
+}
+
+// syncFixture prepares the mocks for a sync run where the source is the sample data and the stored geolocation data
+// is the sample data plus the given stale IPs.
+func syncFixture(t *testing.T, stale ...string) (*mocks.GeolocationDataReader, *mocks.GeolocationDataStorage, *mocks.GeolocationDataMerger, *mocks.GeolocationDataSyncer) {
+	t.Helper()
+
+	// Load sample data
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	reader := syncReader(t, data)
+
+	stored := make([]model.Geolocation, 0, len(data)+len(stale))
+
+	for _, d := range data {
+		geo, err := model.DecodeGeolocation(d)
+		if err != nil || geo.IsValid() != nil {
+			continue
+		}
+
+		stored = append(stored, geo)
+	}
+
+	for _, ip := range stale {
+		stored = append(stored, model.Geolocation{IPAddress: ip})
+	}
+
+	merger := mocks.NewGeolocationDataMerger(t)
+
+	storage := mocks.NewGeolocationDataStorage(t)
+
+	syncer := mocks.NewGeolocationDataSyncer(t)
+	syncer.EXPECT().ListGeolocation(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(geo model.Geolocation) error) error {
+			for _, geo := range stored {
+				if err := fn(geo); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	return reader, storage, merger, syncer
+}
+
+func TestGeolocationDataProcessor_Process_sync(t *testing.T) {
+	t.Parallel()
+
+	reader, storage, merger, syncer := syncFixture(t, "10.0.0.1")
+
+	merger.EXPECT().FindGeolocationsByIP(mock.Anything, mock.Anything).Return(map[string]model.Geolocation{}, nil)
+	merger.EXPECT().MergeGeolocation(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	syncer.EXPECT().SoftDeleteGeolocation(mock.Anything, []string{"10.0.0.1"}).Return(nil)
+
+	processor := NewParseGeolocationData(storage, &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithSync(merger, syncer, SyncOptions{SoftDelete: true, MaxDeletionPercent: 50}),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 3)
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Accepted)
+	assert.Equal(t, &SyncReport{Stored: 5, Stale: 1, SoftDeleted: true}, report.Sync)
+}
+
+func TestGeolocationDataProcessor_Process_sync_too_many_deletions(t *testing.T) {
+	t.Parallel()
+
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	// The stored geolocation data is replaced by as many new IP addresses.
+	stored := make([]model.Geolocation, 0, len(data))
+
+	for i := range data {
+		stored = append(stored, model.Geolocation{IPAddress: fmt.Sprintf("10.0.0.%d", i+1)})
+	}
+
+	syncer := mocks.NewGeolocationDataSyncer(t)
+	syncer.EXPECT().ListGeolocation(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(geo model.Geolocation) error) error {
+			for _, geo := range stored {
+				if err := fn(geo); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	reader := mocks.NewGeolocationDataReader(t)
+	reader.EXPECT().ReadGeolocationData(mock.Anything).
+		RunAndReturn(func(_ context.Context) (<-chan []string, error) {
+			dataCh := make(chan []string, len(data))
+
+			for _, d := range data {
+				dataCh <- d
+			}
+			close(dataCh)
+
+			return dataCh, nil
+		}).
+		Once()
+
+	// Aborted before anything is written, no dataset version and no geolocation data.
+	processor := NewParseGeolocationData(mocks.NewGeolocationDataStorage(t), &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithSync(mocks.NewGeolocationDataMerger(t), syncer, SyncOptions{MaxDeletionPercent: 50}),
+		WithVersioning(mocks.NewGeolocationDataVersioner(t)),
+	)
+
+	_, err = processor.Process(context.Background(), reader, 3)
+	require.ErrorIs(t, err, ErrTooManyDeletions)
+
+	var serr ctxd.StructuredError
+
+	require.ErrorAs(t, err, &serr)
+	assert.Equal(t, 100.0, serr.Fields()["stale_percent"])
+}
+
+func TestGeolocationDataProcessor_Process_sync_rejected(t *testing.T) {
+	t.Parallel()
+
+	data, err := helpers.LoadAllSampleData()
+	require.NoError(t, err)
+
+	stored := make([]model.Geolocation, 0, len(data))
+
+	for _, d := range data {
+		geo, err := model.DecodeGeolocation(d)
+		if err == nil && geo.IsValid() == nil {
+			stored = append(stored, geo)
+		}
+	}
+
+	// 70.95.73.73 is in the source, rejected for its latitude.
+	require.Equal(t, "70.95.73.73", data[2][0])
+	data[2][4] = "north"
+
+	reader := syncReader(t, data)
+
+	merger := mocks.NewGeolocationDataMerger(t)
+	merger.EXPECT().FindGeolocationsByIP(mock.Anything, mock.Anything).Return(map[string]model.Geolocation{}, nil)
+	merger.EXPECT().MergeGeolocation(mock.Anything, mock.Anything, mock.Anything).Return(nil)
+
+	syncer := mocks.NewGeolocationDataSyncer(t)
+	syncer.EXPECT().ListGeolocation(mock.Anything, mock.Anything).
+		RunAndReturn(func(_ context.Context, fn func(geo model.Geolocation) error) error {
+			for _, geo := range stored {
+				if err := fn(geo); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+
+	processor := NewParseGeolocationData(mocks.NewGeolocationDataStorage(t), &ctxd.LoggerMock{},
+		WithSaverWorkers(1),
+		WithSync(merger, syncer, SyncOptions{MaxDeletionPercent: 50}),
+	)
+
+	report, err := processor.Process(context.Background(), reader, 3)
+	require.NoError(t, err)
+
+	// The stored geolocation data of the rejected one is kept.
+	assert.Equal(t, 3, report.Accepted)
+	assert.Equal(t, &SyncReport{Stored: 4, Stale: 0}, report.Sync)
+}
@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+)
+
+//go:generate mockery --name=GeolocationDataVersioner --outpkg=mocks --output=mocks --filename=geolocation_data_versioner.go --with-expecter
+
+// GeolocationDataVersioner is the interface that provides the ability to version the dataset of geolocation data.
+type GeolocationDataVersioner interface {
+	// CreateDatasetVersion creates the dataset version of the import of the source, returns its ID.
+	CreateDatasetVersion(ctx context.Context, source string) (int64, error)
+	// CompleteDatasetVersion marks the dataset version as completed, with the number of geolocation data accepted.
+	CompleteDatasetVersion(ctx context.Context, id int64, accepted int) error
+}
+
+// WithVersioning creates a dataset version for each processing.
+//
+// The geolocation data inserted or changed is stamped with the version. The version is completed once the whole
+// source is processed, it is left incomplete when the processing fails.
+func WithVersioning(versioner GeolocationDataVersioner) ProcessorOption {
+	return func(p *GeolocationDataProcessor) {
+		p.versioner = versioner
+	}
+}
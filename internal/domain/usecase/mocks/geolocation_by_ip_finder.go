@@ -7,6 +7,8 @@ import (
 
 	model "github.com/dohernandez/vio/internal/domain/model"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // GeolocationByIPFinder is an autogenerated mock type for the GeolocationByIPFinder type
@@ -22,9 +24,9 @@ func (_m *GeolocationByIPFinder) EXPECT() *GeolocationByIPFinder_Expecter {
 	return &GeolocationByIPFinder_Expecter{mock: &_m.Mock}
 }
 
-// FindGeolocationByIP provides a mock function with given fields: ctx, ip
-func (_m *GeolocationByIPFinder) FindGeolocationByIP(ctx context.Context, ip string) (model.Geolocation, error) {
-	ret := _m.Called(ctx, ip)
+// FindGeolocationByIP provides a mock function with given fields: ctx, ip, datasets
+func (_m *GeolocationByIPFinder) FindGeolocationByIP(ctx context.Context, ip string, datasets []string) (model.Geolocation, error) {
+	ret := _m.Called(ctx, ip, datasets)
 
 	if len(ret) == 0 {
 		panic("no return value specified for FindGeolocationByIP")
@@ -32,17 +34,17 @@ func (_m *GeolocationByIPFinder) FindGeolocationByIP(ctx context.Context, ip str
 
 	var r0 model.Geolocation
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) (model.Geolocation, error)); ok {
-		return rf(ctx, ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) (model.Geolocation, error)); ok {
+		return rf(ctx, ip, datasets)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) model.Geolocation); ok {
-		r0 = rf(ctx, ip)
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) model.Geolocation); ok {
+		r0 = rf(ctx, ip, datasets)
 	} else {
 		r0 = ret.Get(0).(model.Geolocation)
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, ip)
+	if rf, ok := ret.Get(1).(func(context.Context, string, []string) error); ok {
+		r1 = rf(ctx, ip, datasets)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -58,13 +60,14 @@ type GeolocationByIPFinder_FindGeolocationByIP_Call struct {
 // FindGeolocationByIP is a helper method to define mock.On call
 //   - ctx context.Context
 //   - ip string
-func (_e *GeolocationByIPFinder_Expecter) FindGeolocationByIP(ctx interface{}, ip interface{}) *GeolocationByIPFinder_FindGeolocationByIP_Call {
-	return &GeolocationByIPFinder_FindGeolocationByIP_Call{Call: _e.mock.On("FindGeolocationByIP", ctx, ip)}
+//   - datasets []string
+func (_e *GeolocationByIPFinder_Expecter) FindGeolocationByIP(ctx interface{}, ip interface{}, datasets interface{}) *GeolocationByIPFinder_FindGeolocationByIP_Call {
+	return &GeolocationByIPFinder_FindGeolocationByIP_Call{Call: _e.mock.On("FindGeolocationByIP", ctx, ip, datasets)}
 }
 
-func (_c *GeolocationByIPFinder_FindGeolocationByIP_Call) Run(run func(ctx context.Context, ip string)) *GeolocationByIPFinder_FindGeolocationByIP_Call {
+func (_c *GeolocationByIPFinder_FindGeolocationByIP_Call) Run(run func(ctx context.Context, ip string, datasets []string)) *GeolocationByIPFinder_FindGeolocationByIP_Call {
 	_c.Call.Run(func(args mock.Arguments) {
-		run(args[0].(context.Context), args[1].(string))
+		run(args[0].(context.Context), args[1].(string), args[2].([]string))
 	})
 	return _c
 }
@@ -74,7 +77,66 @@ func (_c *GeolocationByIPFinder_FindGeolocationByIP_Call) Return(_a0 model.Geolo
 	return _c
 }
 
-func (_c *GeolocationByIPFinder_FindGeolocationByIP_Call) RunAndReturn(run func(context.Context, string) (model.Geolocation, error)) *GeolocationByIPFinder_FindGeolocationByIP_Call {
+func (_c *GeolocationByIPFinder_FindGeolocationByIP_Call) RunAndReturn(run func(context.Context, string, []string) (model.Geolocation, error)) *GeolocationByIPFinder_FindGeolocationByIP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindGeolocationByIPAsOf provides a mock function with given fields: ctx, ip, asOf, datasets
+func (_m *GeolocationByIPFinder) FindGeolocationByIPAsOf(ctx context.Context, ip string, asOf time.Time, datasets []string) (model.Geolocation, error) {
+	ret := _m.Called(ctx, ip, asOf, datasets)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindGeolocationByIPAsOf")
+	}
+
+	var r0 model.Geolocation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, []string) (model.Geolocation, error)); ok {
+		return rf(ctx, ip, asOf, datasets)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time, []string) model.Geolocation); ok {
+		r0 = rf(ctx, ip, asOf, datasets)
+	} else {
+		r0 = ret.Get(0).(model.Geolocation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time, []string) error); ok {
+		r1 = rf(ctx, ip, asOf, datasets)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeolocationByIPFinder_FindGeolocationByIPAsOf_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindGeolocationByIPAsOf'
+type GeolocationByIPFinder_FindGeolocationByIPAsOf_Call struct {
+	*mock.Call
+}
+
+// FindGeolocationByIPAsOf is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ip string
+//   - asOf time.Time
+//   - datasets []string
+func (_e *GeolocationByIPFinder_Expecter) FindGeolocationByIPAsOf(ctx interface{}, ip interface{}, asOf interface{}, datasets interface{}) *GeolocationByIPFinder_FindGeolocationByIPAsOf_Call {
+	return &GeolocationByIPFinder_FindGeolocationByIPAsOf_Call{Call: _e.mock.On("FindGeolocationByIPAsOf", ctx, ip, asOf, datasets)}
+}
+
+func (_c *GeolocationByIPFinder_FindGeolocationByIPAsOf_Call) Run(run func(ctx context.Context, ip string, asOf time.Time, datasets []string)) *GeolocationByIPFinder_FindGeolocationByIPAsOf_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string), args[2].(time.Time), args[3].([]string))
+	})
+	return _c
+}
+
+func (_c *GeolocationByIPFinder_FindGeolocationByIPAsOf_Call) Return(_a0 model.Geolocation, _a1 error) *GeolocationByIPFinder_FindGeolocationByIPAsOf_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GeolocationByIPFinder_FindGeolocationByIPAsOf_Call) RunAndReturn(run func(context.Context, string, time.Time, []string) (model.Geolocation, error)) *GeolocationByIPFinder_FindGeolocationByIPAsOf_Call {
 	_c.Call.Return(run)
 	return _c
 }
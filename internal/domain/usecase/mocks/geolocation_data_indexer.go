@@ -0,0 +1,128 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeolocationDataIndexer is an autogenerated mock type for the GeolocationDataIndexer type
+type GeolocationDataIndexer struct {
+	mock.Mock
+}
+
+type GeolocationDataIndexer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GeolocationDataIndexer) EXPECT() *GeolocationDataIndexer_Expecter {
+	return &GeolocationDataIndexer_Expecter{mock: &_m.Mock}
+}
+
+// DropGeolocationIndexes provides a mock function with given fields: ctx
+func (_m *GeolocationDataIndexer) DropGeolocationIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DropGeolocationIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataIndexer_DropGeolocationIndexes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DropGeolocationIndexes'
+type GeolocationDataIndexer_DropGeolocationIndexes_Call struct {
+	*mock.Call
+}
+
+// DropGeolocationIndexes is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *GeolocationDataIndexer_Expecter) DropGeolocationIndexes(ctx interface{}) *GeolocationDataIndexer_DropGeolocationIndexes_Call {
+	return &GeolocationDataIndexer_DropGeolocationIndexes_Call{Call: _e.mock.On("DropGeolocationIndexes", ctx)}
+}
+
+func (_c *GeolocationDataIndexer_DropGeolocationIndexes_Call) Run(run func(ctx context.Context)) *GeolocationDataIndexer_DropGeolocationIndexes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataIndexer_DropGeolocationIndexes_Call) Return(_a0 error) *GeolocationDataIndexer_DropGeolocationIndexes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataIndexer_DropGeolocationIndexes_Call) RunAndReturn(run func(context.Context) error) *GeolocationDataIndexer_DropGeolocationIndexes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RestoreGeolocationIndexes provides a mock function with given fields: ctx
+func (_m *GeolocationDataIndexer) RestoreGeolocationIndexes(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestoreGeolocationIndexes")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataIndexer_RestoreGeolocationIndexes_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestoreGeolocationIndexes'
+type GeolocationDataIndexer_RestoreGeolocationIndexes_Call struct {
+	*mock.Call
+}
+
+// RestoreGeolocationIndexes is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *GeolocationDataIndexer_Expecter) RestoreGeolocationIndexes(ctx interface{}) *GeolocationDataIndexer_RestoreGeolocationIndexes_Call {
+	return &GeolocationDataIndexer_RestoreGeolocationIndexes_Call{Call: _e.mock.On("RestoreGeolocationIndexes", ctx)}
+}
+
+func (_c *GeolocationDataIndexer_RestoreGeolocationIndexes_Call) Run(run func(ctx context.Context)) *GeolocationDataIndexer_RestoreGeolocationIndexes_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataIndexer_RestoreGeolocationIndexes_Call) Return(_a0 error) *GeolocationDataIndexer_RestoreGeolocationIndexes_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataIndexer_RestoreGeolocationIndexes_Call) RunAndReturn(run func(context.Context) error) *GeolocationDataIndexer_RestoreGeolocationIndexes_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGeolocationDataIndexer creates a new instance of GeolocationDataIndexer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeolocationDataIndexer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeolocationDataIndexer {
+	mock := &GeolocationDataIndexer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
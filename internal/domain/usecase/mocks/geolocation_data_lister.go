@@ -0,0 +1,84 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/dohernandez/vio/internal/domain/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeolocationDataLister is an autogenerated mock type for the GeolocationDataLister type
+type GeolocationDataLister struct {
+	mock.Mock
+}
+
+type GeolocationDataLister_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GeolocationDataLister) EXPECT() *GeolocationDataLister_Expecter {
+	return &GeolocationDataLister_Expecter{mock: &_m.Mock}
+}
+
+// ListGeolocation provides a mock function with given fields: ctx, fn
+func (_m *GeolocationDataLister) ListGeolocation(ctx context.Context, fn func(model.Geolocation) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListGeolocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(model.Geolocation) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataLister_ListGeolocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListGeolocation'
+type GeolocationDataLister_ListGeolocation_Call struct {
+	*mock.Call
+}
+
+// ListGeolocation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(model.Geolocation) error
+func (_e *GeolocationDataLister_Expecter) ListGeolocation(ctx interface{}, fn interface{}) *GeolocationDataLister_ListGeolocation_Call {
+	return &GeolocationDataLister_ListGeolocation_Call{Call: _e.mock.On("ListGeolocation", ctx, fn)}
+}
+
+func (_c *GeolocationDataLister_ListGeolocation_Call) Run(run func(ctx context.Context, fn func(model.Geolocation) error)) *GeolocationDataLister_ListGeolocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(model.Geolocation) error))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataLister_ListGeolocation_Call) Return(_a0 error) *GeolocationDataLister_ListGeolocation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataLister_ListGeolocation_Call) RunAndReturn(run func(context.Context, func(model.Geolocation) error) error) *GeolocationDataLister_ListGeolocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGeolocationDataLister creates a new instance of GeolocationDataLister. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeolocationDataLister(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeolocationDataLister {
+	mock := &GeolocationDataLister{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
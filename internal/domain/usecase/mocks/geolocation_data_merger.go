@@ -0,0 +1,144 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/dohernandez/vio/internal/domain/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeolocationDataMerger is an autogenerated mock type for the GeolocationDataMerger type
+type GeolocationDataMerger struct {
+	mock.Mock
+}
+
+type GeolocationDataMerger_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GeolocationDataMerger) EXPECT() *GeolocationDataMerger_Expecter {
+	return &GeolocationDataMerger_Expecter{mock: &_m.Mock}
+}
+
+// FindGeolocationsByIP provides a mock function with given fields: ctx, ips
+func (_m *GeolocationDataMerger) FindGeolocationsByIP(ctx context.Context, ips []string) (map[string]model.Geolocation, error) {
+	ret := _m.Called(ctx, ips)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindGeolocationsByIP")
+	}
+
+	var r0 map[string]model.Geolocation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]model.Geolocation, error)); ok {
+		return rf(ctx, ips)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]model.Geolocation); ok {
+		r0 = rf(ctx, ips)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]model.Geolocation)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, ips)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeolocationDataMerger_FindGeolocationsByIP_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindGeolocationsByIP'
+type GeolocationDataMerger_FindGeolocationsByIP_Call struct {
+	*mock.Call
+}
+
+// FindGeolocationsByIP is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ips []string
+func (_e *GeolocationDataMerger_Expecter) FindGeolocationsByIP(ctx interface{}, ips interface{}) *GeolocationDataMerger_FindGeolocationsByIP_Call {
+	return &GeolocationDataMerger_FindGeolocationsByIP_Call{Call: _e.mock.On("FindGeolocationsByIP", ctx, ips)}
+}
+
+func (_c *GeolocationDataMerger_FindGeolocationsByIP_Call) Run(run func(ctx context.Context, ips []string)) *GeolocationDataMerger_FindGeolocationsByIP_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataMerger_FindGeolocationsByIP_Call) Return(_a0 map[string]model.Geolocation, _a1 error) *GeolocationDataMerger_FindGeolocationsByIP_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GeolocationDataMerger_FindGeolocationsByIP_Call) RunAndReturn(run func(context.Context, []string) (map[string]model.Geolocation, error)) *GeolocationDataMerger_FindGeolocationsByIP_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MergeGeolocation provides a mock function with given fields: ctx, added, changed
+func (_m *GeolocationDataMerger) MergeGeolocation(ctx context.Context, added []*model.Geolocation, changed []*model.Geolocation) error {
+	ret := _m.Called(ctx, added, changed)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MergeGeolocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []*model.Geolocation, []*model.Geolocation) error); ok {
+		r0 = rf(ctx, added, changed)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataMerger_MergeGeolocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MergeGeolocation'
+type GeolocationDataMerger_MergeGeolocation_Call struct {
+	*mock.Call
+}
+
+// MergeGeolocation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - added []*model.Geolocation
+//   - changed []*model.Geolocation
+func (_e *GeolocationDataMerger_Expecter) MergeGeolocation(ctx interface{}, added interface{}, changed interface{}) *GeolocationDataMerger_MergeGeolocation_Call {
+	return &GeolocationDataMerger_MergeGeolocation_Call{Call: _e.mock.On("MergeGeolocation", ctx, added, changed)}
+}
+
+func (_c *GeolocationDataMerger_MergeGeolocation_Call) Run(run func(ctx context.Context, added []*model.Geolocation, changed []*model.Geolocation)) *GeolocationDataMerger_MergeGeolocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]*model.Geolocation), args[2].([]*model.Geolocation))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataMerger_MergeGeolocation_Call) Return(_a0 error) *GeolocationDataMerger_MergeGeolocation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataMerger_MergeGeolocation_Call) RunAndReturn(run func(context.Context, []*model.Geolocation, []*model.Geolocation) error) *GeolocationDataMerger_MergeGeolocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGeolocationDataMerger creates a new instance of GeolocationDataMerger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeolocationDataMerger(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeolocationDataMerger {
+	mock := &GeolocationDataMerger{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
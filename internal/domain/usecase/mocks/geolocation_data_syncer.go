@@ -0,0 +1,178 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	model "github.com/dohernandez/vio/internal/domain/model"
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeolocationDataSyncer is an autogenerated mock type for the GeolocationDataSyncer type
+type GeolocationDataSyncer struct {
+	mock.Mock
+}
+
+type GeolocationDataSyncer_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GeolocationDataSyncer) EXPECT() *GeolocationDataSyncer_Expecter {
+	return &GeolocationDataSyncer_Expecter{mock: &_m.Mock}
+}
+
+// DeleteGeolocation provides a mock function with given fields: ctx, ips
+func (_m *GeolocationDataSyncer) DeleteGeolocation(ctx context.Context, ips []string) error {
+	ret := _m.Called(ctx, ips)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteGeolocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, ips)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataSyncer_DeleteGeolocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteGeolocation'
+type GeolocationDataSyncer_DeleteGeolocation_Call struct {
+	*mock.Call
+}
+
+// DeleteGeolocation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ips []string
+func (_e *GeolocationDataSyncer_Expecter) DeleteGeolocation(ctx interface{}, ips interface{}) *GeolocationDataSyncer_DeleteGeolocation_Call {
+	return &GeolocationDataSyncer_DeleteGeolocation_Call{Call: _e.mock.On("DeleteGeolocation", ctx, ips)}
+}
+
+func (_c *GeolocationDataSyncer_DeleteGeolocation_Call) Run(run func(ctx context.Context, ips []string)) *GeolocationDataSyncer_DeleteGeolocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataSyncer_DeleteGeolocation_Call) Return(_a0 error) *GeolocationDataSyncer_DeleteGeolocation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataSyncer_DeleteGeolocation_Call) RunAndReturn(run func(context.Context, []string) error) *GeolocationDataSyncer_DeleteGeolocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListGeolocation provides a mock function with given fields: ctx, fn
+func (_m *GeolocationDataSyncer) ListGeolocation(ctx context.Context, fn func(model.Geolocation) error) error {
+	ret := _m.Called(ctx, fn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListGeolocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, func(model.Geolocation) error) error); ok {
+		r0 = rf(ctx, fn)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataSyncer_ListGeolocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListGeolocation'
+type GeolocationDataSyncer_ListGeolocation_Call struct {
+	*mock.Call
+}
+
+// ListGeolocation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - fn func(model.Geolocation) error
+func (_e *GeolocationDataSyncer_Expecter) ListGeolocation(ctx interface{}, fn interface{}) *GeolocationDataSyncer_ListGeolocation_Call {
+	return &GeolocationDataSyncer_ListGeolocation_Call{Call: _e.mock.On("ListGeolocation", ctx, fn)}
+}
+
+func (_c *GeolocationDataSyncer_ListGeolocation_Call) Run(run func(ctx context.Context, fn func(model.Geolocation) error)) *GeolocationDataSyncer_ListGeolocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(func(model.Geolocation) error))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataSyncer_ListGeolocation_Call) Return(_a0 error) *GeolocationDataSyncer_ListGeolocation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataSyncer_ListGeolocation_Call) RunAndReturn(run func(context.Context, func(model.Geolocation) error) error) *GeolocationDataSyncer_ListGeolocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SoftDeleteGeolocation provides a mock function with given fields: ctx, ips
+func (_m *GeolocationDataSyncer) SoftDeleteGeolocation(ctx context.Context, ips []string) error {
+	ret := _m.Called(ctx, ips)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SoftDeleteGeolocation")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, ips)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataSyncer_SoftDeleteGeolocation_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SoftDeleteGeolocation'
+type GeolocationDataSyncer_SoftDeleteGeolocation_Call struct {
+	*mock.Call
+}
+
+// SoftDeleteGeolocation is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ips []string
+func (_e *GeolocationDataSyncer_Expecter) SoftDeleteGeolocation(ctx interface{}, ips interface{}) *GeolocationDataSyncer_SoftDeleteGeolocation_Call {
+	return &GeolocationDataSyncer_SoftDeleteGeolocation_Call{Call: _e.mock.On("SoftDeleteGeolocation", ctx, ips)}
+}
+
+func (_c *GeolocationDataSyncer_SoftDeleteGeolocation_Call) Run(run func(ctx context.Context, ips []string)) *GeolocationDataSyncer_SoftDeleteGeolocation_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].([]string))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataSyncer_SoftDeleteGeolocation_Call) Return(_a0 error) *GeolocationDataSyncer_SoftDeleteGeolocation_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataSyncer_SoftDeleteGeolocation_Call) RunAndReturn(run func(context.Context, []string) error) *GeolocationDataSyncer_SoftDeleteGeolocation_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGeolocationDataSyncer creates a new instance of GeolocationDataSyncer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeolocationDataSyncer(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeolocationDataSyncer {
+	mock := &GeolocationDataSyncer{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
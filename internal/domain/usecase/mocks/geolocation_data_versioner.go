@@ -0,0 +1,141 @@
+// Code generated by mockery v2.46.3. DO NOT EDIT.
+
+package mocks
+
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
+
+// GeolocationDataVersioner is an autogenerated mock type for the GeolocationDataVersioner type
+type GeolocationDataVersioner struct {
+	mock.Mock
+}
+
+type GeolocationDataVersioner_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *GeolocationDataVersioner) EXPECT() *GeolocationDataVersioner_Expecter {
+	return &GeolocationDataVersioner_Expecter{mock: &_m.Mock}
+}
+
+// CompleteDatasetVersion provides a mock function with given fields: ctx, id, accepted
+func (_m *GeolocationDataVersioner) CompleteDatasetVersion(ctx context.Context, id int64, accepted int) error {
+	ret := _m.Called(ctx, id, accepted)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CompleteDatasetVersion")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, int) error); ok {
+		r0 = rf(ctx, id, accepted)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GeolocationDataVersioner_CompleteDatasetVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CompleteDatasetVersion'
+type GeolocationDataVersioner_CompleteDatasetVersion_Call struct {
+	*mock.Call
+}
+
+// CompleteDatasetVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int64
+//   - accepted int
+func (_e *GeolocationDataVersioner_Expecter) CompleteDatasetVersion(ctx interface{}, id interface{}, accepted interface{}) *GeolocationDataVersioner_CompleteDatasetVersion_Call {
+	return &GeolocationDataVersioner_CompleteDatasetVersion_Call{Call: _e.mock.On("CompleteDatasetVersion", ctx, id, accepted)}
+}
+
+func (_c *GeolocationDataVersioner_CompleteDatasetVersion_Call) Run(run func(ctx context.Context, id int64, accepted int)) *GeolocationDataVersioner_CompleteDatasetVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(int64), args[2].(int))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataVersioner_CompleteDatasetVersion_Call) Return(_a0 error) *GeolocationDataVersioner_CompleteDatasetVersion_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *GeolocationDataVersioner_CompleteDatasetVersion_Call) RunAndReturn(run func(context.Context, int64, int) error) *GeolocationDataVersioner_CompleteDatasetVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateDatasetVersion provides a mock function with given fields: ctx, source
+func (_m *GeolocationDataVersioner) CreateDatasetVersion(ctx context.Context, source string) (int64, error) {
+	ret := _m.Called(ctx, source)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateDatasetVersion")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (int64, error)); ok {
+		return rf(ctx, source)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(ctx, source)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, source)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GeolocationDataVersioner_CreateDatasetVersion_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateDatasetVersion'
+type GeolocationDataVersioner_CreateDatasetVersion_Call struct {
+	*mock.Call
+}
+
+// CreateDatasetVersion is a helper method to define mock.On call
+//   - ctx context.Context
+//   - source string
+func (_e *GeolocationDataVersioner_Expecter) CreateDatasetVersion(ctx interface{}, source interface{}) *GeolocationDataVersioner_CreateDatasetVersion_Call {
+	return &GeolocationDataVersioner_CreateDatasetVersion_Call{Call: _e.mock.On("CreateDatasetVersion", ctx, source)}
+}
+
+func (_c *GeolocationDataVersioner_CreateDatasetVersion_Call) Run(run func(ctx context.Context, source string)) *GeolocationDataVersioner_CreateDatasetVersion_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context), args[1].(string))
+	})
+	return _c
+}
+
+func (_c *GeolocationDataVersioner_CreateDatasetVersion_Call) Return(_a0 int64, _a1 error) *GeolocationDataVersioner_CreateDatasetVersion_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *GeolocationDataVersioner_CreateDatasetVersion_Call) RunAndReturn(run func(context.Context, string) (int64, error)) *GeolocationDataVersioner_CreateDatasetVersion_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewGeolocationDataVersioner creates a new instance of GeolocationDataVersioner. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewGeolocationDataVersioner(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *GeolocationDataVersioner {
+	mock := &GeolocationDataVersioner{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
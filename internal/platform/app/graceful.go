@@ -6,6 +6,11 @@ import (
 
 // GracefulDBShutdown close all db opened connection.
 func GracefulDBShutdown(ctx context.Context, l *Locator) {
+	// Nothing to close with the in-memory database.
+	if l.DBx == nil {
+		return
+	}
+
 	if err := l.DBx.Close(); err != nil {
 		l.LoggerProvider.CtxdLogger().Error(
 			ctx,
@@ -14,4 +19,19 @@ func GracefulDBShutdown(ctx context.Context, l *Locator) {
 			err,
 		)
 	}
+
+	if l.CopyPool != nil {
+		l.CopyPool.Close()
+	}
+
+	for _, db := range l.ReplicaDBx {
+		if err := db.Close(); err != nil {
+			l.LoggerProvider.CtxdLogger().Error(
+				ctx,
+				"Failed to close connection to the read replica",
+				"error",
+				err,
+			)
+		}
+	}
 }
@@ -3,8 +3,10 @@ package app
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Masterminds/squirrel"
@@ -20,25 +22,48 @@ import (
 	grpcLogging "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
 	grpcRecovery "github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/recovery"
 	grpc_prometheus "github.com/grpc-ecosystem/go-grpc-prometheus"
+	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib" // Postgres driver
 	"github.com/jmoiron/sqlx"
 	"github.com/nhatthm/go-clock"
 	clockSrv "github.com/nhatthm/go-clock/service"
 	"github.com/opencensus-integrations/ocsql"
+	"github.com/prometheus/client_golang/prometheus"
 	"google.golang.org/grpc"
+	_ "modernc.org/sqlite" // SQLite driver
 )
 
-const driver = "pgx"
+const (
+	driver       = "pgx"
+	sqliteDriver = "sqlite"
+)
+
+// sqlitePragmas are the pragmas set up on the SQLite connections, before the ones of the DSN. The saver workers write
+// concurrently, waiting for the lock instead of failing.
+const sqlitePragmas = "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
 
 type locatorOptions struct {
 	enableService bool
 	grpcOpts      []servers.Option
 	grpcRestOpts  []servers.Option
 
+	// dataset is the dataset the geolocation data is written to.
+	dataset string
+	// bulkLoad saves the geolocation data with COPY on Postgres.
+	bulkLoad bool
+
 	enableMetrics bool
 	metricsOpts   []servers.Option
 }
 
+// geolocationRepository is the repository of the geolocation data, backed by the database or the memory.
+type geolocationRepository interface {
+	usecase.GeolocationDataStorage
+	usecase.GeolocationDataMerger
+	usecase.GeolocationDataSyncer
+	usecase.GeolocationByIPFinder
+}
+
 // Option sets up service locator.
 type Option func(l *Locator)
 
@@ -49,6 +74,20 @@ func WithNoService() Option {
 	}
 }
 
+// WithDataset sets the dataset the geolocation data is written to. Defaults to model.DefaultDataset.
+func WithDataset(name string) Option {
+	return func(l *Locator) {
+		l.opts.dataset = name
+	}
+}
+
+// WithBulkLoad saves the geolocation data with COPY instead of batched inserts, Postgres only.
+func WithBulkLoad() Option {
+	return func(l *Locator) {
+		l.opts.bulkLoad = true
+	}
+}
+
 // WithGRPCOptions sets up gRPC server options.
 func WithGRPCOptions(opts ...servers.Option) Option {
 	return func(l *Locator) {
@@ -79,6 +118,12 @@ type Locator struct {
 
 	DBx     *sqlx.DB
 	Storage *sqluct.Storage
+	// ReplicaDBx are the read replicas of DBx, the service balances the lookups by IP across.
+	ReplicaDBx []*sqlx.DB
+	// CopyPool is the connection pool copying the geolocation data to Postgres, set with WithBulkLoad.
+	CopyPool *pgxpool.Pool
+	// Memory is the in-memory database, set instead of DBx and Storage when the DSN is memory://.
+	Memory *storage.Memory
 
 	logger *zapctxd.Logger
 	ctxd.LoggerProvider
@@ -93,7 +138,11 @@ type Locator struct {
 	VioMetricsService *servers.Metrics
 
 	// storage
-	geoRepo *storage.Geolocation
+	replicas    *storage.Replicas
+	geoRepo     geolocationRepository
+	geoCopier   usecase.GeolocationDataStorage
+	geoIndexer  usecase.GeolocationDataIndexer
+	versionRepo usecase.GeolocationDataVersioner
 
 	// use cases
 	geolocationByIP *usecase.GeolocationByIPExposer
@@ -118,15 +167,35 @@ func NewServiceLocator(cfg *config.Config, opts ...Option) (*Locator, error) {
 	// logger stuff
 	l.setLogger()
 
-	// Database stuff.
-	l.Config.PostgresDB.DriverName = driver
-	//
-	l.DBx, err = makeDBx(cfg.PostgresDB)
-	if err != nil {
-		return nil, err
-	}
+	// Database stuff, the storage backend is selected by the scheme of the DSN.
+	switch cfg.PostgresDB.Backend() {
+	case config.BackendMemory:
+		l.Memory, err = storage.OpenMemory(cfg.PostgresDB.DSN)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		dbCfg, dialect := driverConfig(cfg.PostgresDB)
+		l.Config.PostgresDB.DriverName = dbCfg.DriverName
+
+		l.DBx, err = makeDBx(dbCfg)
+		if err != nil {
+			return nil, err
+		}
 
-	l.Storage = makeStorage(l.DBx, l.CtxdLogger())
+		l.Storage = makeStorage(l.DBx, dialect, l.CtxdLogger())
+
+		if err := l.setupReplicas(dialect); err != nil {
+			return nil, err
+		}
+
+		if l.opts.bulkLoad && dialect == sqluct.DialectPostgres {
+			l.CopyPool, err = makeCopyPool(dbCfg)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	// setting up storage deps
 	l.setupStorage()
@@ -222,14 +291,66 @@ func makeDB(cfg config.DBConfig) (*sql.DB, error) {
 	return db, nil
 }
 
+// makeCopyPool initializes the connection pool copying to Postgres, sized as the database.
+func makeCopyPool(cfg config.DBConfig) (*pgxpool.Pool, error) {
+	poolCfg, err := pgxpool.ParseConfig(cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		poolCfg.MaxConns = int32(cfg.MaxOpenConns) //nolint:gosec // Bounded by the configuration.
+	}
+
+	if cfg.MaxLifetime > 0 {
+		poolCfg.MaxConnLifetime = cfg.MaxLifetime
+	}
+
+	return pgxpool.NewWithConfig(context.Background(), poolCfg)
+}
+
+// driverConfig returns the configuration of the database driver of the DSN, Postgres or SQLite, along with the
+// dialect of the database.
+func driverConfig(cfg config.DBConfig) (config.DBConfig, sqluct.Dialect) {
+	if cfg.Backend() == config.BackendSQLite {
+		cfg.DriverName = sqliteDriver
+		cfg.DSN = sqliteDSN(cfg.DSN)
+
+		return cfg, sqluct.DialectSQLite3
+	}
+
+	cfg.DriverName = driver
+
+	return cfg, sqluct.DialectPostgres
+}
+
+// sqliteDSN returns the DSN of the SQLite driver from the DSN sqlite://path?query, i.e. sqlite://vio.db or
+// sqlite:///var/lib/vio/vio.db.
+func sqliteDSN(dsn string) string {
+	path := strings.TrimPrefix(dsn, config.BackendSQLite+"://")
+
+	path, query, _ := strings.Cut(path, "?")
+	if query != "" {
+		query = "&" + query
+	}
+
+	return path + "?" + sqlitePragmas + query
+}
+
 // makeStorage initializes database storage.
 func makeStorage(
 	db *sqlx.DB,
+	dialect sqluct.Dialect,
 	logger ctxd.Logger,
 ) *sqluct.Storage {
 	st := sqluct.NewStorage(db)
 
 	st.Format = squirrel.Dollar
+
+	if dialect == sqluct.DialectSQLite3 {
+		st.Format = squirrel.Question
+		st.Mapper = &sqluct.Mapper{Dialect: dialect}
+	}
 	st.OnError = func(ctx context.Context, err error) {
 		logger.Error(ctx, "storage failure", "error", err)
 	}
@@ -237,12 +358,66 @@ func makeStorage(
 	return st
 }
 
+// errReplicaBackend is the error of a read replica with a storage backend other than the one of the primary.
+var errReplicaBackend = errors.New("replica backend does not match the primary one")
+
+// setupReplicas opens the read replicas the service balances the lookups by IP across. The commands work with the
+// primary only.
+func (l *Locator) setupReplicas(dialect sqluct.Dialect) error {
+	if !l.opts.enableService || len(l.Config.PostgresDB.ReplicaDSNs) == 0 {
+		return nil
+	}
+
+	replicas := make([]*sqluct.Storage, 0, len(l.Config.PostgresDB.ReplicaDSNs))
+
+	for _, dsn := range l.Config.PostgresDB.ReplicaDSNs {
+		dbCfg := l.Config.PostgresDB
+		dbCfg.DSN = dsn
+
+		if dbCfg.Backend() != l.Config.PostgresDB.Backend() {
+			return fmt.Errorf("%w: %s", errReplicaBackend, dbCfg.Backend())
+		}
+
+		dbCfg, _ = driverConfig(dbCfg)
+
+		db, err := makeDBx(dbCfg)
+		if err != nil {
+			return err
+		}
+
+		l.ReplicaDBx = append(l.ReplicaDBx, db)
+		replicas = append(replicas, makeStorage(db, dialect, l.CtxdLogger()))
+	}
+
+	l.replicas = storage.NewReplicas(replicas,
+		storage.WithReplicaCooldown(l.Config.PostgresDB.ReplicaCooldown),
+		storage.WithReplicaClock(l.Clock().Now),
+	)
+
+	return nil
+}
+
 func (l *Locator) setupStorage() {
-	l.geoRepo = storage.NewGeolocation(l.Storage)
+	if l.Memory != nil {
+		l.geoRepo = storage.NewMemoryGeolocation(l.Memory, storage.WithDataset(l.opts.dataset))
+		l.versionRepo = storage.NewMemoryDatasetVersion(l.Memory, storage.WithDataset(l.opts.dataset))
+
+		return
+	}
+
+	geo := storage.NewGeolocation(l.Storage, storage.WithDataset(l.opts.dataset), storage.WithReplicas(l.replicas))
+
+	l.geoRepo = geo
+	l.geoIndexer = geo
+	l.versionRepo = storage.NewDatasetVersion(l.Storage, storage.WithDataset(l.opts.dataset))
+
+	if l.CopyPool != nil {
+		l.geoCopier = storage.NewGeolocationCopier(l.CopyPool, storage.WithDataset(l.opts.dataset))
+	}
 }
 
 func (l *Locator) setupUsecaseDependencies() {
-	l.geolocationByIP = usecase.NewGeolocationByIPExposer(l.geoRepo)
+	l.geolocationByIP = usecase.NewGeolocationByIPExposer(l.geoRepo, l.Config.Datasets...)
 }
 
 func (l *Locator) setGRPCUnitaryInterceptors() {
@@ -273,6 +448,10 @@ func (l *Locator) setupServices() error {
 		)
 
 		grpc_prometheus.EnableHandlingTimeHistogram()
+
+		if err := l.registerReplicasMetrics(); err != nil {
+			return err
+		}
 	}
 
 	l.VioService = service.NewVioService(
@@ -280,6 +459,7 @@ func (l *Locator) setupServices() error {
 			Config: servers.Config{
 				Name: "grpc " + l.Config.ServiceName,
 			},
+			AdminToken: l.Config.AdminToken,
 		},
 		l.geolocationByIP,
 		grpcOpts...,
@@ -335,6 +515,28 @@ func (l *Locator) setupServices() error {
 	return nil
 }
 
+// registerReplicasMetrics exposes the number of healthy read replicas, replacing the one of a former Locator.
+func (l *Locator) registerReplicasMetrics() error {
+	if l.replicas == nil {
+		return nil
+	}
+
+	healthy := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "vio_replicas_healthy",
+		Help: "Number of read replicas serving the lookups, the others are left out for the cooldown.",
+	}, func() float64 {
+		return float64(l.replicas.Healthy())
+	})
+
+	prometheus.Unregister(healthy)
+
+	if err := prometheus.Register(healthy); err != nil {
+		return ctxd.WrapError(context.Background(), err, "registering replicas metrics")
+	}
+
+	return nil
+}
+
 // grpcInterceptorLogger adapts zapctxd logger to interceptor logger.
 func grpcInterceptorLogger(l *zapctxd.Logger) grpcLogging.Logger {
 	return grpcLogging.LoggerFunc(func(ctx context.Context, lvl grpcLogging.Level, msg string, _ ...any) {
@@ -353,7 +555,37 @@ func grpcInterceptorLogger(l *zapctxd.Logger) grpcLogging.Logger {
 	})
 }
 
-// GeoStorage returns geolocation data storage.
+// GeoStorage returns geolocation data storage, copying the geolocation data with WithBulkLoad on Postgres.
 func (l *Locator) GeoStorage() usecase.GeolocationDataStorage {
+	if l.geoCopier != nil {
+		return l.geoCopier
+	}
+
+	return l.geoRepo
+}
+
+// GeoMerger returns geolocation data merger, used by incremental imports.
+func (l *Locator) GeoMerger() usecase.GeolocationDataMerger {
+	return l.geoRepo
+}
+
+// GeoLister returns geolocation data lister.
+func (l *Locator) GeoLister() usecase.GeolocationDataLister {
+	return l.geoRepo
+}
+
+// GeoSyncer returns geolocation data syncer, used by sync imports.
+func (l *Locator) GeoSyncer() usecase.GeolocationDataSyncer {
 	return l.geoRepo
 }
+
+// GeoVersioner returns geolocation dataset versioner, each import creates a dataset version.
+func (l *Locator) GeoVersioner() usecase.GeolocationDataVersioner {
+	return l.versionRepo
+}
+
+// GeoIndexer returns geolocation data indexer, used by bulk loads. It is nil with the in-memory database, which has
+// no index to drop.
+func (l *Locator) GeoIndexer() usecase.GeolocationDataIndexer {
+	return l.geoIndexer
+}
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/platform/config"
+	"github.com/dohernandez/vio/internal/platform/storage"
+)
+
+// ErrNoMigrations is the error migrating the in-memory storage, which has no schema.
+var ErrNoMigrations = errors.New("no migrations for the in-memory storage")
+
+// NewMigrator returns the migrator of the database of the DSN, on a connection of its own closed with the migrator.
+func NewMigrator(cfg config.DBConfig) (*storage.Migrator, error) {
+	if cfg.Backend() == config.BackendMemory {
+		return nil, ErrNoMigrations
+	}
+
+	cfg, dialect := driverConfig(cfg)
+
+	db, err := sql.Open(cfg.DriverName, cfg.DSN)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := storage.NewMigrator(db, dialect)
+	if err != nil {
+		_ = db.Close() //nolint:errcheck
+
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// AutoMigrate applies the migrations not yet applied to the database, when enabled with AUTO_MIGRATE.
+//
+// Instances starting at once wait for each other under the advisory lock of the migrator, the first one applies the
+// migrations.
+func AutoMigrate(ctx context.Context, l *Locator) error {
+	if !l.Config.AutoMigrate || l.Config.PostgresDB.Backend() == config.BackendMemory {
+		return nil
+	}
+
+	m, err := NewMigrator(l.Config.PostgresDB)
+	if err != nil {
+		return ctxd.WrapError(ctx, err, "failed to init migrator")
+	}
+
+	defer func() {
+		if err := m.Close(); err != nil {
+			l.CtxdLogger().Error(ctx, "Failed to close migrator", "error", err)
+		}
+	}()
+
+	if err := m.Up(ctx); err != nil {
+		return err
+	}
+
+	version, _, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.CtxdLogger().Important(ctx, "Database migrated", "version", version)
+
+	return nil
+}
@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/domain/usecase"
+	"github.com/dohernandez/vio/internal/platform/app"
+	"github.com/dohernandez/vio/internal/platform/config"
+	readplatform "github.com/dohernandez/vio/internal/platform/reader"
+	"github.com/urfave/cli/v2"
+)
+
+var diffFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:        "output",
+		Usage:       "Output format of the differences (text, json, yaml).",
+		Required:    false,
+		DefaultText: reportText,
+		Value:       reportText,
+		Aliases:     []string{"o"},
+	},
+}
+
+// diffFilesystem compares the geolocation data of a file from a filesystem against the stored one.
+func diffFilesystem(c *cli.Context) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return ctxd.WrapError(c.Context, err, "failed to load configurations")
+	}
+
+	duplicates, err := duplicatePolicy(c)
+	if err != nil {
+		return err
+	}
+
+	decodeOpts, err := decodeOptions(c)
+	if err != nil {
+		return err
+	}
+
+	dataset := c.String("dataset")
+	if err := model.CheckDatasetName(dataset); err != nil {
+		return err
+	}
+
+	rules, err := loadRules(c.Context, c.String("rules"))
+	if err != nil {
+		return err
+	}
+
+	// initialize locator
+	deps, err := app.NewServiceLocator(cfg, app.WithNoService(), app.WithDataset(dataset))
+	if err != nil {
+		return ctxd.WrapError(c.Context, err, "failed to initialize service locator")
+	}
+
+	// initialize reader
+	reader := readplatform.NewFileSystem(c.String("file"), deps.CtxdLogger())
+
+	// The geolocation data is compared as parse would store it.
+	differ := usecase.NewGeolocationDataDiffer(deps.GeoLister(), deps.CtxdLogger(),
+		usecase.WithDiffDuplicatePolicy(duplicates),
+		usecase.WithDiffDecodeOptions(decodeOpts...),
+		usecase.WithDiffRules(rules),
+	)
+
+	diff, err := differ.Diff(c.Context, reader)
+	if err != nil {
+		return err
+	}
+
+	if format := c.String("output"); format != reportText {
+		return encode(c.Context, c.App.Writer, format, diff)
+	}
+
+	return writeTextDiff(c.App.Writer, diff)
+}
+
+// writeTextDiff writes the differences in a human-readable way, prefixing added geolocation data with '+', removed
+// with '-' and changed with '~'.
+func writeTextDiff(w io.Writer, diff usecase.Diff) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	for _, geo := range diff.Added {
+		_, _ = fmt.Fprintf(tw, "+ %s\n", formatGeolocation(geo))
+	}
+
+	for _, geo := range diff.Removed {
+		_, _ = fmt.Fprintf(tw, "- %s\n", formatGeolocation(geo))
+	}
+
+	for _, change := range diff.Changed {
+		_, _ = fmt.Fprintf(tw, "~ %s\n", change.IPAddress)
+
+		for _, fc := range change.Changes {
+			_, _ = fmt.Fprintf(tw, "    %s:\t%q -> %q\n", fc.Field, fc.Old, fc.New)
+		}
+	}
+
+	_, _ = fmt.Fprintf(tw, "added %d, removed %d, changed %d, unchanged %d, discarded %d\n",
+		len(diff.Added), len(diff.Removed), len(diff.Changed), diff.Unchanged, diff.Discarded)
+
+	return tw.Flush()
+}
+
+func formatGeolocation(geo model.Geolocation) string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s\t%s\t%s\t%s",
+		geo.IPAddress,
+		geo.CountryCode,
+		geo.Country,
+		geo.City,
+		strconv.FormatFloat(geo.Latitude, 'f', -1, 64),
+		strconv.FormatFloat(geo.Longitude, 'f', -1, 64),
+		strconv.FormatInt(geo.MysteryValue, 10),
+	)
+}
@@ -0,0 +1,177 @@
+package cli
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/platform/app"
+	"github.com/dohernandez/vio/internal/platform/config"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/urfave/cli/v2"
+)
+
+var migrateDownFlags = []cli.Flag{
+	&cli.UintFlag{
+		Name:        "steps",
+		Usage:       "Number of migrations to roll back.",
+		Required:    false,
+		DefaultText: "1",
+		Value:       1,
+		Aliases:     []string{"n"},
+	},
+	&cli.BoolFlag{
+		Name:     "all",
+		Usage:    "Roll back all the migrations.",
+		Required: false,
+	},
+}
+
+var migrateStatusFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:        "output",
+		Usage:       "Output format of the status (text, json, yaml).",
+		Required:    false,
+		DefaultText: reportText,
+		Value:       reportText,
+		Aliases:     []string{"o"},
+	},
+}
+
+// migrateCommand is the command managing the database migrations embedded in the binary.
+func migrateCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "Manage the database migrations embedded in the binary.",
+		Subcommands: []*cli.Command{
+			{
+				Name:   "up",
+				Usage:  "Apply the migrations not yet applied.",
+				Action: migrateUp,
+			},
+			{
+				Name:   "down",
+				Usage:  "Roll back the last migrations applied.",
+				Flags:  migrateDownFlags,
+				Action: migrateDown,
+			},
+			{
+				Name:   "status",
+				Usage:  "List the migrations and whether they are applied.",
+				Flags:  migrateStatusFlags,
+				Action: migrateStatus,
+			},
+			{
+				Name:   "version",
+				Usage:  "Print the version of the last migration applied.",
+				Action: migrateVersion,
+			},
+		},
+	}
+}
+
+// withMigrator calls fn with the migrator of the configured database.
+func withMigrator(c *cli.Context, fn func(m *storage.Migrator) error) (err error) {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return ctxd.WrapError(c.Context, err, "failed to load configurations")
+	}
+
+	m, err := app.NewMigrator(cfg.PostgresDB)
+	if err != nil {
+		return ctxd.WrapError(c.Context, err, "failed to initialize migrator")
+	}
+
+	defer func() {
+		if cerr := m.Close(); cerr != nil && err == nil {
+			err = ctxd.WrapError(c.Context, cerr, "failed to close migrator")
+		}
+	}()
+
+	return fn(m)
+}
+
+// migrateUp applies the migrations not yet applied.
+func migrateUp(c *cli.Context) error {
+	return withMigrator(c, func(m *storage.Migrator) error {
+		if err := m.Up(c.Context); err != nil {
+			return err
+		}
+
+		return writeVersion(c, m)
+	})
+}
+
+// migrateDown rolls back the last migrations applied.
+func migrateDown(c *cli.Context) error {
+	steps := c.Uint("steps")
+	if c.Bool("all") {
+		steps = 0
+	} else if steps == 0 {
+		return ctxd.NewError(c.Context, "invalid number of steps, use --all to roll back all the migrations")
+	}
+
+	return withMigrator(c, func(m *storage.Migrator) error {
+		if err := m.Down(c.Context, steps); err != nil {
+			return err
+		}
+
+		return writeVersion(c, m)
+	})
+}
+
+// migrateStatus lists the migrations and whether they are applied.
+func migrateStatus(c *cli.Context) error {
+	return withMigrator(c, func(m *storage.Migrator) error {
+		statuses, err := m.Status(c.Context)
+		if err != nil {
+			return err
+		}
+
+		if format := c.String("output"); format != reportText {
+			return encode(c.Context, c.App.Writer, format, statuses)
+		}
+
+		tw := tabwriter.NewWriter(c.App.Writer, 0, 0, 2, ' ', 0)
+
+		_, _ = fmt.Fprintln(tw, "VERSION\tNAME\tSTATUS")
+
+		for _, s := range statuses {
+			status := "pending"
+
+			switch {
+			case s.Dirty:
+				status = "dirty"
+			case s.Applied:
+				status = "applied"
+			}
+
+			_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\n", s.Version, s.Name, status)
+		}
+
+		return tw.Flush()
+	})
+}
+
+// migrateVersion prints the version of the last migration applied.
+func migrateVersion(c *cli.Context) error {
+	return withMigrator(c, func(m *storage.Migrator) error {
+		return writeVersion(c, m)
+	})
+}
+
+// writeVersion writes the version of the last migration applied, 0 when none is, flagged when dirty.
+func writeVersion(c *cli.Context, m *storage.Migrator) error {
+	version, dirty, err := m.Version(c.Context)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		_, err = fmt.Fprintf(c.App.Writer, "%d (dirty)\n", version)
+	} else {
+		_, err = fmt.Fprintf(c.App.Writer, "%d\n", version)
+	}
+
+	return err
+}
@@ -0,0 +1,71 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/usecase"
+)
+
+// progressFunc returns the function reporting the import progress.
+//
+// When w is an interactive terminal, the progress is displayed in a single line updated in place, otherwise it is
+// logged periodically.
+func progressFunc(w io.Writer, logger ctxd.Logger) usecase.ProgressFunc {
+	if isTerminal(w) {
+		return func(_ context.Context, p usecase.Progress) {
+			_, _ = fmt.Fprintf(w, "\r\033[K%s", formatProgress(p))
+
+			if p.Done {
+				_, _ = fmt.Fprintln(w)
+			}
+		}
+	}
+
+	return func(ctx context.Context, p usecase.Progress) {
+		logger.Important(ctx, "geolocation data progress",
+			"read", p.Read,
+			"accepted", p.Accepted,
+			"discarded", p.Discarded,
+			"rate_rps", p.Rate,
+			"bytes_consumed", p.BytesConsumed,
+			"bytes_total", p.BytesTotal,
+			"eta_s", p.ETA.Seconds(),
+			"elapsed_s", p.Elapsed.Seconds(),
+		)
+	}
+}
+
+func formatProgress(p usecase.Progress) string {
+	line := fmt.Sprintf("read %d, accepted %d, discarded %d, %.0f rows/s, elapsed %s",
+		p.Read, p.Accepted, p.Discarded, p.Rate, p.Elapsed.Truncate(time.Second))
+
+	if p.BytesTotal > 0 {
+		line += fmt.Sprintf(", %.1f%%", float64(p.BytesConsumed)*100/float64(p.BytesTotal))
+	}
+
+	if p.ETA > 0 {
+		line += fmt.Sprintf(", ETA %s", p.ETA.Truncate(time.Second))
+	}
+
+	return line
+}
+
+// isTerminal checks whether w is a character device, i.e. an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return st.Mode()&os.ModeCharDevice != 0
+}
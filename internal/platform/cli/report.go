@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/usecase"
+	"github.com/urfave/cli/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Report formats.
+const (
+	reportJSON = "json"
+	reportYAML = "yaml"
+	reportText = "text"
+)
+
+var reportFlags = []cli.Flag{
+	&cli.StringFlag{
+		Name:     "report",
+		Usage:    "Emit the import report in the given format (json, yaml, text).",
+		Required: false,
+		EnvVars:  []string{"REPORT"},
+	},
+	&cli.StringFlag{
+		Name:     "report-file",
+		Usage:    "File to write the import report to. Defaults to the standard output. Uses json format when --report is not set.",
+		Required: false,
+		EnvVars:  []string{"REPORT_FILE"},
+	},
+}
+
+// emitReport writes the report according to the --report and --report-file flags.
+func emitReport(c *cli.Context, report usecase.Report) error {
+	format := c.String("report")
+	file := c.String("report-file")
+
+	if format == "" && file == "" {
+		return nil
+	}
+
+	if format == "" {
+		format = reportJSON
+	}
+
+	if file == "" {
+		return writeReport(c.Context, c.App.Writer, format, report)
+	}
+
+	f, err := os.Create(file) //nolint:gosec
+	if err != nil {
+		return ctxd.WrapError(c.Context, err, "creating report file", "file", file)
+	}
+
+	if err = writeReport(c.Context, f, format, report); err != nil {
+		_ = f.Close() //nolint:errcheck
+
+		return err
+	}
+
+	return f.Close()
+}
+
+// writeReport writes the report to w in the given format.
+func writeReport(ctx context.Context, w io.Writer, format string, report usecase.Report) error {
+	if format == reportText {
+		return writeTextReport(w, report)
+	}
+
+	return encode(ctx, w, format, report)
+}
+
+// encode writes v to w in json or yaml format.
+func encode(ctx context.Context, w io.Writer, format string, v any) error {
+	switch format {
+	case reportJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(v)
+	case reportYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+
+		return enc.Close()
+	default:
+		return ctxd.NewError(ctx, "unsupported format", "format", format)
+	}
+}
+
+func writeTextReport(w io.Writer, report usecase.Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	if report.Version != 0 {
+		_, _ = fmt.Fprintf(tw, "version:\t%d\n", report.Version)
+	}
+
+	_, _ = fmt.Fprintf(tw, "read:\t%d\n", report.Read)
+	_, _ = fmt.Fprintf(tw, "accepted:\t%d\n", report.Accepted)
+	_, _ = fmt.Fprintf(tw, "discarded:\t%d\n", report.Discarded)
+
+	reasons := make([]string, 0, len(report.DiscardedReasons))
+
+	for reason := range report.DiscardedReasons {
+		reasons = append(reasons, reason)
+	}
+
+	sort.Strings(reasons)
+
+	for _, reason := range reasons {
+		_, _ = fmt.Fprintf(tw, "  %s:\t%d\n", reason, report.DiscardedReasons[reason])
+	}
+
+	rules := make([]string, 0, len(report.Rules))
+
+	for rule := range report.Rules {
+		rules = append(rules, rule)
+	}
+
+	sort.Strings(rules)
+
+	for _, rule := range rules {
+		_, _ = fmt.Fprintf(tw, "rule %s:\trejected %d, warned %d\n", rule, report.Rules[rule].Rejected, report.Rules[rule].Warned)
+	}
+
+	if report.Merge != nil {
+		_, _ = fmt.Fprintf(tw, "new:\t%d\n", report.Merge.New)
+		_, _ = fmt.Fprintf(tw, "changed:\t%d\n", report.Merge.Changed)
+		_, _ = fmt.Fprintf(tw, "unchanged:\t%d\n", report.Merge.Unchanged)
+	}
+
+	if report.Sync != nil {
+		_, _ = fmt.Fprintf(tw, "stored:\t%d\n", report.Sync.Stored)
+		_, _ = fmt.Fprintf(tw, "stale:\t%d\n", report.Sync.Stale)
+		_, _ = fmt.Fprintf(tw, "soft deleted:\t%t\n", report.Sync.SoftDeleted)
+	}
+
+	_, _ = fmt.Fprintf(tw, "duration:\t%.3fs\n", report.DurationSeconds)
+	_, _ = fmt.Fprintf(tw, "throughput:\t%.2f records/s\n", report.Throughput)
+
+	for _, file := range report.Files {
+		_, _ = fmt.Fprintf(tw, "file %s:\t%d bytes, read %d, accepted %d, discarded %d\n",
+			file.Name, file.SizeBytes, file.Read, file.Accepted, file.Discarded)
+	}
+
+	return tw.Flush()
+}
@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"os"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"gopkg.in/yaml.v3"
+)
+
+// rulesFile is the file configuring the validation rules, in yaml or json format.
+type rulesFile struct {
+	Rules []model.RuleConfig `json:"rules" yaml:"rules"`
+}
+
+// loadRules loads the validation rules from the file. Returns the default rules when file is empty.
+func loadRules(ctx context.Context, file string) (*model.RuleSet, error) {
+	if file == "" {
+		return model.DefaultRuleSet(), nil
+	}
+
+	data, err := os.ReadFile(file) //nolint:gosec
+	if err != nil {
+		return nil, ctxd.WrapError(ctx, err, "reading rules file", "file", file)
+	}
+
+	var cfg rulesFile
+
+	if err = yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, ctxd.WrapError(ctx, err, "parsing rules file", "file", file)
+	}
+
+	rules, err := model.NewRuleSet(cfg.Rules...)
+	if err != nil {
+		return nil, ctxd.WrapError(ctx, err, "invalid rules file", "file", file)
+	}
+
+	return rules, nil
+}
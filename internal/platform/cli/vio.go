@@ -1,15 +1,29 @@
 package cli
 
 import (
+	"errors"
+	"os"
+	"time"
+
 	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
 	"github.com/dohernandez/vio/internal/domain/usecase"
 	"github.com/dohernandez/vio/internal/platform/app"
 	"github.com/dohernandez/vio/internal/platform/config"
 	readplatform "github.com/dohernandez/vio/internal/platform/reader"
+	"github.com/dohernandez/vio/pkg/database"
+	"github.com/dohernandez/vio/pkg/database/pgx"
 	"github.com/urfave/cli/v2"
 	"go.uber.org/zap/zapcore"
 )
 
+// Import modes.
+const (
+	modeInsert      = "insert"
+	modeIncremental = "incremental"
+	modeSync        = "sync"
+)
+
 var parseFlags = []cli.Flag{
 	&cli.StringFlag{
 		Name:        "parallel",
@@ -20,6 +34,109 @@ var parseFlags = []cli.Flag{
 		EnvVars:     []string{"PARALLEL"},
 		Aliases:     []string{"p"},
 	},
+	&cli.DurationFlag{
+		Name:        "progress-interval",
+		Usage:       "Interval to report the parsing progress. Zero disables the progress report.",
+		Required:    false,
+		DefaultText: "5s",
+		Value:       5 * time.Second,
+		EnvVars:     []string{"PROGRESS_INTERVAL"},
+	},
+	&cli.UintFlag{
+		Name:        "batch-size",
+		Usage:       "Number of geolocation data saved at once, up to the maximum of the database backend. Initial batch size when auto-tuning.",
+		Required:    false,
+		DefaultText: "500",
+		Value:       500,
+		EnvVars:     []string{"BATCH_SIZE"},
+	},
+	&cli.DurationFlag{
+		Name:     "batch-autotune",
+		Usage:    "Target insert latency to auto-tune the batch size. Zero disables auto-tuning.",
+		Required: false,
+		EnvVars:  []string{"BATCH_AUTOTUNE"},
+	},
+	&cli.UintFlag{
+		Name:        "saver-workers",
+		Usage:       "Number of workers saving geolocation data in parallel.",
+		Required:    false,
+		DefaultText: "15",
+		Value:       15,
+		EnvVars:     []string{"SAVER_WORKERS"},
+	},
+	&cli.UintFlag{
+		Name:        "retries",
+		Usage:       "Number of times a batch failing with a transient storage error, i.e. a connection loss, is saved again. Zero disables the retries.",
+		Required:    false,
+		DefaultText: "3",
+		Value:       3,
+		EnvVars:     []string{"RETRIES"},
+	},
+	&cli.DurationFlag{
+		Name:        "retry-backoff",
+		Usage:       "Wait before the first retry of a batch, doubled on each retry up to 5s.",
+		Required:    false,
+		DefaultText: "100ms",
+		Value:       100 * time.Millisecond,
+		EnvVars:     []string{"RETRY_BACKOFF"},
+	},
+	&cli.UintFlag{
+		Name:        "read-buffer",
+		Usage:       "Buffer size of the channel the geolocation data is read into.",
+		Required:    false,
+		DefaultText: "1000",
+		Value:       1000,
+		EnvVars:     []string{"READ_BUFFER"},
+	},
+	&cli.UintFlag{
+		Name:        "save-buffer",
+		Usage:       "Buffer size of the channel holding the geolocation data ready to be saved.",
+		Required:    false,
+		DefaultText: "2 * batch-size * saver-workers",
+		EnvVars:     []string{"SAVE_BUFFER"},
+	},
+	&cli.StringFlag{
+		Name:        "mode",
+		Usage:       "Import mode (insert, incremental, sync). Incremental only writes new and changed geolocation data. Sync also removes the stored geolocation data not found in the file.",
+		Required:    false,
+		DefaultText: modeInsert,
+		Value:       modeInsert,
+		EnvVars:     []string{"IMPORT_MODE"},
+	},
+	&cli.BoolFlag{
+		Name:     "soft-delete",
+		Usage:    "Mark the stale geolocation data as deleted instead of deleting it, in sync mode.",
+		Required: false,
+		EnvVars:  []string{"SOFT_DELETE"},
+	},
+	&cli.Float64Flag{
+		Name:        "max-deletion",
+		Usage:       "Maximum percentage of the stored geolocation data allowed to be removed in sync mode.",
+		Required:    false,
+		DefaultText: "10",
+		Value:       10,
+		EnvVars:     []string{"MAX_DELETION_PERCENT"},
+	},
+	&cli.BoolFlag{
+		Name:     "bulk",
+		Usage:    "Drop the indexes of the stored geolocation data while loading, and copy it on Postgres, in insert mode. Lookups are slow until the indexes are recreated.",
+		Required: false,
+		EnvVars:  []string{"BULK_LOAD"},
+	},
+	&cli.UintFlag{
+		Name:        "dedup-max-keys",
+		Usage:       "Number of IP addresses held in memory to find the duplicates, past which they are spilled to a temporary file. Zero holds them all in memory. Keeping the last duplicate holds the data in memory regardless.",
+		Required:    false,
+		DefaultText: "0",
+		EnvVars:     []string{"DEDUP_MAX_KEYS"},
+	},
+	&cli.StringFlag{
+		Name:        "dedup-spill-dir",
+		Usage:       "Directory of the temporary file the IP addresses are spilled to.",
+		Required:    false,
+		DefaultText: "system temporary directory",
+		EnvVars:     []string{"DEDUP_SPILL_DIR"},
+	},
 	&cli.BoolFlag{
 		Name:        "verbose",
 		Required:    false,
@@ -39,6 +156,52 @@ var parseFilesystemFlags = []cli.Flag{
 		EnvVars:     []string{"FILE", "DATA_FILE"},
 		Aliases:     []string{"f"},
 	},
+	&cli.StringFlag{
+		Name:        "dataset",
+		Usage:       "Dataset the geolocation data belongs to, i.e. the provider. Each dataset is imported and looked up side by side.",
+		Required:    false,
+		DefaultText: model.DefaultDataset,
+		Value:       model.DefaultDataset,
+		EnvVars:     []string{"DATASET"},
+	},
+	&cli.StringFlag{
+		Name:        "ipv4-mapped",
+		Usage:       "How IPv4-mapped IPv6 addresses are stored (unmap, keep). Unmap stores ::ffff:1.2.3.4 as 1.2.3.4.",
+		Required:    false,
+		DefaultText: string(model.UnmapIPv4),
+		Value:       string(model.UnmapIPv4),
+		EnvVars:     []string{"IPV4_MAPPED"},
+	},
+	&cli.StringFlag{
+		Name:        "country",
+		Usage:       "How the country name is checked against the country code (lenient, strict, correct). Strict discards the mismatches, correct replaces the name with the ISO 3166-1 one.",
+		Required:    false,
+		DefaultText: string(model.CountryLenient),
+		Value:       string(model.CountryLenient),
+		EnvVars:     []string{"COUNTRY_POLICY"},
+	},
+	&cli.StringFlag{
+		Name:        "mystery-fraction",
+		Usage:       "How a fractional mystery value is handled (reject, round). Round rounds it half away from zero.",
+		Required:    false,
+		DefaultText: string(model.FractionReject),
+		Value:       string(model.FractionReject),
+		EnvVars:     []string{"MYSTERY_FRACTION"},
+	},
+	&cli.StringFlag{
+		Name:        "duplicates",
+		Usage:       "Geolocation data kept when an IP address is found more than once (first, last). Keeping the last one holds the data in memory until the whole file is read.",
+		Required:    false,
+		DefaultText: "first",
+		Value:       string(usecase.FirstWins),
+		EnvVars:     []string{"DUPLICATES"},
+	},
+	&cli.StringFlag{
+		Name:     "rules",
+		Usage:    "File configuring the validation rules (yaml or json). See resources/rules.example.yaml.",
+		Required: false,
+		EnvVars:  []string{"RULES_FILE"},
+	},
 }
 
 // NewCliApp creates a new cli app.
@@ -52,39 +215,184 @@ func NewCliApp() *cli.App {
 				Usage: "Load, parse and store geolocation data.",
 				Subcommands: []*cli.Command{
 					{
-						Name:  "filesystem",
-						Usage: "Parse geolocation data from a file from a filesystem.",
-						Flags: append(parseFlags, parseFilesystemFlags...),
-						Action: func(c *cli.Context) error {
-							cfg, err := config.GetConfig()
-							if err != nil {
-								return ctxd.WrapError(c.Context, err, "failed to load configurations")
-							}
-
-							// set log level
-							if c.Bool("verbose") {
-								cfg.Log.Level = zapcore.DebugLevel
-								// set output to command line writer
-								cfg.Log.Output = c.App.Writer
-							}
-
-							// initialize locator
-							deps, err := app.NewServiceLocator(cfg, app.WithNoService())
-							if err != nil {
-								return ctxd.WrapError(c.Context, err, "failed to initialize service locator")
-							}
-
-							// initialize reader
-							reader := readplatform.NewFileSystem(c.String("file"), deps.CtxdLogger())
-
-							// parse data
-							parser := usecase.NewParseGeolocationData(deps.GeoStorage(), deps.CtxdLogger())
-
-							return parser.Process(c.Context, reader, c.Uint("parallel"))
-						},
+						Name:   "filesystem",
+						Usage:  "Parse geolocation data from a file from a filesystem.",
+						Flags:  append(append(parseFlags, parseFilesystemFlags...), reportFlags...),
+						Action: parseFilesystem,
 					},
 				},
 			},
+			{
+				Name:   "diff",
+				Usage:  "Compare the geolocation data of a file against the stored one, before parsing it.",
+				Flags:  append(parseFilesystemFlags, diffFlags...),
+				Action: diffFilesystem,
+			},
+			migrateCommand(),
 		},
 	}
 }
+
+// parseFilesystem parses geolocation data from a file from a filesystem.
+func parseFilesystem(c *cli.Context) error {
+	cfg, err := config.GetConfig()
+	if err != nil {
+		return ctxd.WrapError(c.Context, err, "failed to load configurations")
+	}
+
+	mode := c.String("mode")
+	if mode != modeInsert && mode != modeIncremental && mode != modeSync {
+		return ctxd.NewError(c.Context, "invalid import mode", "mode", mode)
+	}
+
+	bulk := c.Bool("bulk")
+	if bulk && mode != modeInsert {
+		return ctxd.NewError(c.Context, "bulk load requires the insert mode", "mode", mode)
+	}
+
+	duplicates, err := duplicatePolicy(c)
+	if err != nil {
+		return err
+	}
+
+	decodeOpts, err := decodeOptions(c)
+	if err != nil {
+		return err
+	}
+
+	dataset := c.String("dataset")
+	if err := model.CheckDatasetName(dataset); err != nil {
+		return err
+	}
+
+	rules, err := loadRules(c.Context, c.String("rules"))
+	if err != nil {
+		return err
+	}
+
+	// set log level
+	if c.Bool("verbose") {
+		cfg.Log.Level = zapcore.DebugLevel
+		// set output to command line writer
+		cfg.Log.Output = c.App.Writer
+	}
+
+	// initialize locator
+	locatorOpts := []app.Option{app.WithNoService(), app.WithDataset(dataset)}
+
+	if bulk {
+		if cfg.PostgresDB.Backend() == config.BackendMemory {
+			return ctxd.NewError(c.Context, "bulk load requires a database backend")
+		}
+
+		locatorOpts = append(locatorOpts, app.WithBulkLoad())
+	}
+
+	deps, err := app.NewServiceLocator(cfg, locatorOpts...)
+	if err != nil {
+		return ctxd.WrapError(c.Context, err, "failed to initialize service locator")
+	}
+
+	if l, ok := deps.GeoStorage().(usecase.GeolocationDataBatchLimiter); ok && c.Uint("batch-size") > uint(l.MaxBatchSize()) {
+		return ctxd.NewError(c.Context, "batch size too large", "batch-size", c.Uint("batch-size"), "max", l.MaxBatchSize())
+	}
+
+	// initialize reader
+	reader := readplatform.NewFileSystem(
+		c.String("file"),
+		deps.CtxdLogger(),
+		readplatform.WithDataBuffer(int(c.Uint("read-buffer"))),
+	)
+
+	// report progress to stderr when interactive, otherwise to the logs
+	errWriter := c.App.ErrWriter
+	if errWriter == nil {
+		errWriter = os.Stderr
+	}
+
+	opts := []usecase.ProcessorOption{
+		usecase.WithBatchSize(int(c.Uint("batch-size"))),
+		usecase.WithBatchAutoTune(c.Duration("batch-autotune")),
+		usecase.WithSaverWorkers(int(c.Uint("saver-workers"))),
+		usecase.WithReadyBuffer(int(c.Uint("save-buffer"))),
+		usecase.WithRetry(usecase.RetryOptions{
+			Retries:   int(c.Uint("retries")),
+			Backoff:   c.Duration("retry-backoff"),
+			Retryable: pgx.IsRetryable,
+			DataError: isDataError,
+		}),
+		usecase.WithDuplicatePolicy(duplicates),
+		usecase.WithDuplicationSpill(c.String("dedup-spill-dir"), int(c.Uint("dedup-max-keys"))),
+		usecase.WithDecodeOptions(decodeOpts...),
+		usecase.WithRules(rules),
+		usecase.WithVersioning(deps.GeoVersioner()),
+		usecase.WithProgress(
+			c.Duration("progress-interval"),
+			progressFunc(errWriter, deps.CtxdLogger()),
+		),
+	}
+
+	if bulk {
+		opts = append(opts, usecase.WithBulkLoad(deps.GeoIndexer()))
+	}
+
+	switch mode {
+	case modeIncremental:
+		opts = append(opts, usecase.WithIncremental(deps.GeoMerger()))
+	case modeSync:
+		opts = append(opts, usecase.WithSync(deps.GeoMerger(), deps.GeoSyncer(), usecase.SyncOptions{
+			SoftDelete:         c.Bool("soft-delete"),
+			MaxDeletionPercent: c.Float64("max-deletion"),
+		}))
+	}
+
+	// parse data
+	parser := usecase.NewParseGeolocationData(deps.GeoStorage(), deps.CtxdLogger(), opts...)
+
+	report, err := parser.Process(c.Context, reader, c.Uint("parallel"))
+	if err != nil {
+		return err
+	}
+
+	return emitReport(c, report)
+}
+
+// decodeOptions returns the options decoding the geolocation data according to the flags.
+func decodeOptions(c *cli.Context) ([]model.DecodeOption, error) {
+	policy := model.MappedIPv4Policy(c.String("ipv4-mapped"))
+	if policy != model.UnmapIPv4 && policy != model.KeepMappedIPv4 {
+		return nil, ctxd.NewError(c.Context, "invalid ipv4-mapped policy", "ipv4-mapped", policy)
+	}
+
+	country := model.CountryPolicy(c.String("country"))
+	if country != model.CountryLenient && country != model.CountryStrict && country != model.CountryCorrect {
+		return nil, ctxd.NewError(c.Context, "invalid country policy", "country", country)
+	}
+
+	fraction := model.FractionPolicy(c.String("mystery-fraction"))
+	if fraction != model.FractionReject && fraction != model.FractionRound {
+		return nil, ctxd.NewError(c.Context, "invalid mystery-fraction policy", "mystery-fraction", fraction)
+	}
+
+	return []model.DecodeOption{
+		model.WithMappedIPv4Policy(policy),
+		model.WithCountryPolicy(country),
+		model.WithFractionPolicy(fraction),
+	}, nil
+}
+
+// duplicatePolicy returns the policy keeping the geolocation data of an IP address found more than once.
+func duplicatePolicy(c *cli.Context) (usecase.DuplicatePolicy, error) {
+	duplicates := usecase.DuplicatePolicy(c.String("duplicates"))
+	if duplicates != usecase.FirstWins && duplicates != usecase.LastWins {
+		return "", ctxd.NewError(c.Context, "invalid duplicates policy", "duplicates", duplicates)
+	}
+
+	return duplicates, nil
+}
+
+// isDataError tells whether the storage error is caused by the geolocation data, a unique violation being reported as
+// database.ErrAlreadyExists.
+func isDataError(err error) bool {
+	return pgx.IsDataError(err) || errors.Is(err, database.ErrAlreadyExists)
+}
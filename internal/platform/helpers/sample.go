@@ -20,8 +20,13 @@ var (
 // When limit is -1, it loads all the data.
 // When offset is -1, it loads the header too.
 func LoadSampleData(limit, offset int) ([][]string, error) {
+	return loadSampleFile("test_data.csv", limit, offset)
+}
+
+// loadSampleFile loads sample data from the given CSV file of the sample data directory.
+func loadSampleFile(name string, limit, offset int) ([][]string, error) {
 	// Open the CSV file.
-	file, err := os.Open(filepath.Join(basePath, "../../../resources/sample_data/test_data.csv")) //nolint:gosec
+	file, err := os.Open(filepath.Join(basePath, "../../../resources/sample_data", name)) //nolint:gosec
 	if err != nil {
 		return nil, fmt.Errorf("opening file: %w", err)
 	}
@@ -93,3 +98,13 @@ func LoadSampleData(limit, offset int) ([][]string, error) {
 func LoadAllSampleData() ([][]string, error) {
 	return LoadSampleData(-1, 0)
 }
+
+// LoadIPv6SampleData loads all the sample data with IPv6 addresses, written in different text forms, from a CSV file.
+func LoadIPv6SampleData() ([][]string, error) {
+	return loadSampleFile("test_data_ipv6.csv", -1, 0)
+}
+
+// LoadExtendedSampleData loads all the sample data with the optional columns, including the header, from a CSV file.
+func LoadExtendedSampleData() ([][]string, error) {
+	return loadSampleFile("test_data_extended.csv", -1, -1)
+}
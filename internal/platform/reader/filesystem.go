@@ -6,26 +6,54 @@ import (
 	"errors"
 	"io"
 	"os"
+	"sync/atomic"
 
 	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/usecase"
 )
 
-// dataChBuf is the buffer size for the data channel.
+// dataChBuf is the default buffer size for the data channel.
 const dataChBuf = 1000
 
 // FileSystem is a storage that save/loads data to/from a file.
 type FileSystem struct {
 	file string
 
+	// consumed is the number of bytes read from the file so far, by the last ReadGeolocationData.
+	consumed atomic.Int64
+	// header is the first record of the file, set by ReadGeolocationData.
+	header atomic.Pointer[[]string]
+
+	dataChBuf int
+
 	logger ctxd.Logger
 }
 
+// FileSystemOption sets up FileSystem.
+type FileSystemOption func(f *FileSystem)
+
+// WithDataBuffer sets the buffer size of the channel the geolocation data is read into. Defaults to 1000.
+func WithDataBuffer(size int) FileSystemOption {
+	return func(f *FileSystem) {
+		if size > 0 {
+			f.dataChBuf = size
+		}
+	}
+}
+
 // NewFileSystem creates a new file storage.
-func NewFileSystem(file string, logger ctxd.Logger) *FileSystem {
-	return &FileSystem{
-		file:   file,
-		logger: logger,
+func NewFileSystem(file string, logger ctxd.Logger, opts ...FileSystemOption) *FileSystem {
+	f := &FileSystem{
+		file:      file,
+		dataChBuf: dataChBuf,
+		logger:    logger,
 	}
+
+	for _, o := range opts {
+		o(f)
+	}
+
+	return f
 }
 
 // ReadGeolocationData reads geolocation data from a file.
@@ -35,15 +63,19 @@ func (f *FileSystem) ReadGeolocationData(ctx context.Context) (<-chan []string,
 		return nil, ctxd.NewError(ctx, "opening file", "error", err)
 	}
 
-	reader := csv.NewReader(file)
+	// The file is read from the start again, i.e. by the sync mode.
+	f.consumed.Store(0)
 
-	// skip header
-	_, err = reader.Read()
+	reader := csv.NewReader(&countingReader{r: file, n: &f.consumed})
+
+	header, err := reader.Read()
 	if err != nil {
 		return nil, ctxd.WrapError(ctx, err, "reading header")
 	}
 
-	dataCh := make(chan []string, dataChBuf)
+	f.header.Store(&header)
+
+	dataCh := make(chan []string, f.dataChBuf)
 
 	go func() {
 		defer func() {
@@ -52,7 +84,7 @@ func (f *FileSystem) ReadGeolocationData(ctx context.Context) (<-chan []string,
 			defer file.Close() //nolint:errcheck
 		}()
 
-		for {
+		for ctx.Err() == nil {
 			record, err := reader.Read()
 			if err != nil {
 				if errors.Is(err, io.EOF) {
@@ -64,9 +96,50 @@ func (f *FileSystem) ReadGeolocationData(ctx context.Context) (<-chan []string,
 				return
 			}
 
-			dataCh <- record
+			select {
+			case <-ctx.Done():
+				return // Canceled, nobody is reading anymore.
+			case dataCh <- record:
+			}
 		}
 	}()
 
 	return dataCh, nil
 }
+
+// Header returns the header of the file, nil until ReadGeolocationData is called.
+func (f *FileSystem) Header() []string {
+	if h := f.header.Load(); h != nil {
+		return *h
+	}
+
+	return nil
+}
+
+// SourceInfo describes the file the geolocation data is read from.
+func (f *FileSystem) SourceInfo() usecase.SourceInfo {
+	info := usecase.SourceInfo{
+		Name:     f.file,
+		Consumed: f.consumed.Load(),
+	}
+
+	if st, err := os.Stat(f.file); err == nil {
+		info.Size = st.Size()
+	}
+
+	return info
+}
+
+// countingReader counts the bytes read from the underlying reader.
+type countingReader struct {
+	r io.Reader
+	n *atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+
+	c.n.Add(int64(n))
+
+	return n, err
+}
@@ -26,4 +26,81 @@ func TestFileSystem_ReadGeolocationData(t *testing.T) {
 	}
 
 	require.Len(t, data, 5)
+
+	info := fs.SourceInfo()
+	require.Equal(t, info.Size, info.Consumed)
+
+	// The file is read again from the start.
+	dataCh, err = fs.ReadGeolocationData(context.Background())
+	require.NoError(t, err)
+
+	read := 0
+
+	for range dataCh {
+		read++
+	}
+
+	require.Equal(t, 5, read)
+	require.Equal(t, info, fs.SourceInfo())
+}
+
+func TestFileSystem_ReadGeolocationData_canceled(t *testing.T) {
+	t.Parallel()
+
+	file := "../../../resources/sample_data/test_data.csv"
+	logger := &ctxd.LoggerMock{}
+
+	fs := NewFileSystem(file, logger)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	dataCh, err := fs.ReadGeolocationData(ctx)
+	require.NoError(t, err)
+
+	// The channel is closed without reading the records.
+	read := 0
+
+	for range dataCh {
+		read++
+	}
+
+	require.Zero(t, read)
+}
+
+func TestFileSystem_SourceInfo(t *testing.T) {
+	t.Parallel()
+
+	file := "../../../resources/sample_data/test_data.csv"
+	logger := &ctxd.LoggerMock{}
+
+	fs := NewFileSystem(file, logger)
+
+	info := fs.SourceInfo()
+	require.Equal(t, file, info.Name)
+	require.Positive(t, info.Size)
+}
+
+func TestFileSystem_Header(t *testing.T) {
+	t.Parallel()
+
+	file := "../../../resources/sample_data/test_data_extended.csv"
+	logger := &ctxd.LoggerMock{}
+
+	fs := NewFileSystem(file, logger)
+
+	require.Nil(t, fs.Header())
+
+	dataCh, err := fs.ReadGeolocationData(context.Background())
+	require.NoError(t, err)
+
+	var data [][]string //nolint:prealloc
+
+	for d := range dataCh {
+		data = append(data, d)
+	}
+
+	require.Len(t, data, 4)
+	require.Len(t, fs.Header(), 14)
+	require.Equal(t, "ip_address", fs.Header()[0])
 }
@@ -2,8 +2,10 @@ package service
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
 	"net"
+	"strings"
 
 	"github.com/bool64/ctxd"
 	"github.com/dohernandez/servers"
@@ -12,6 +14,7 @@ import (
 	api "github.com/dohernandez/vio/pkg/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 )
 
@@ -19,6 +22,10 @@ import (
 type VioServiceConfig struct {
 	servers.Config
 
+	// AdminToken authorizes the admin RPCs, sent as bearer token in the authorization metadata. Admin RPCs are
+	// denied when empty.
+	AdminToken string
+
 	logger ctxd.Logger
 }
 
@@ -30,6 +37,7 @@ type VioService struct {
 	api.UnimplementedVioServiceServer
 
 	geoIPFinder *usecase.GeolocationByIPExposer
+	adminToken  string
 
 	logger ctxd.Logger
 }
@@ -38,6 +46,7 @@ type VioService struct {
 func NewVioService(cfg VioServiceConfig, geoIPFinder *usecase.GeolocationByIPExposer, opts ...servers.Option) *VioService {
 	srv := &VioService{
 		geoIPFinder: geoIPFinder,
+		adminToken:  cfg.AdminToken,
 		logger:      cfg.logger,
 	}
 
@@ -59,32 +68,124 @@ func (v *VioService) RegisterService(s grpc.ServiceRegistrar) {
 
 // GeolocationByIPExposer expose the geolocation data by IP.
 //
-// Receives a request with the ip. Responses with the geolocation data otherwise not.
+// Receives a request with the ip, and optionally the time of the geolocation data. Responses with the geolocation
+// data otherwise not.
 func (v *VioService) GeolocationByIPExposer(ctx context.Context, req *api.GeolocationByIPExposerRequest) (*api.GeolocationByIPExposerResponse, error) {
-	if req.GetIp() == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "ip address is required")
+	if err := checkIPAndDatasets(req.GetIp(), req.GetDatasets()); err != nil {
+		return nil, err
+	}
+
+	var (
+		geo model.Geolocation
+		err error
+	)
+
+	if req.GetAsOf() != nil {
+		if err := req.GetAsOf().CheckValid(); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid as of")
+		}
+
+		geo, err = v.geoIPFinder.ExposeGeolocationByIPAsOf(ctx, req.GetIp(), req.GetAsOf().AsTime(), req.GetDatasets()...)
+	} else {
+		geo, err = v.geoIPFinder.ExposeGeolocationByIP(ctx, req.GetIp(), req.GetDatasets()...)
+	}
+
+	if err != nil {
+		return nil, findError(err)
+	}
+
+	return geolocationResponse(geo, req.GetAttributes()...), nil
+}
+
+// GeolocationDetail expose the geolocation data by IP along with its provenance.
+//
+// Admin only, receives a request authorized with the admin token. Responses with the geolocation data, all its
+// attributes, the import run and the source line it came from.
+func (v *VioService) GeolocationDetail(ctx context.Context, req *api.GeolocationDetailRequest) (*api.GeolocationDetailResponse, error) {
+	if err := v.authorizeAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := checkIPAndDatasets(req.GetIp(), req.GetDatasets()); err != nil {
+		return nil, err
+	}
+
+	geo, err := v.geoIPFinder.ExposeGeolocationByIP(ctx, req.GetIp(), req.GetDatasets()...)
+	if err != nil {
+		return nil, findError(err)
 	}
 
-	if net.ParseIP(req.GetIp()) == nil {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid ip address")
+	return &api.GeolocationDetailResponse{
+		Geolocation: geolocationResponse(geo),
+		RunId:       geo.VersionID,
+		SourceFile:  geo.SourceFile,
+		SourceLine:  geo.SourceLine,
+	}, nil
+}
+
+// authorizeAdmin checks the request carries the admin token as bearer token in the authorization metadata.
+func (v *VioService) authorizeAdmin(ctx context.Context) error {
+	if v.adminToken == "" {
+		return status.Errorf(codes.PermissionDenied, "admin access is disabled")
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	for _, auth := range md.Get("authorization") {
+		token, ok := strings.CutPrefix(auth, "Bearer ")
+		if ok && subtle.ConstantTimeCompare([]byte(token), []byte(v.adminToken)) == 1 {
+			return nil
+		}
+	}
+
+	return status.Errorf(codes.Unauthenticated, "invalid admin token")
+}
+
+// checkIPAndDatasets validates the ip and the datasets of a request.
+func checkIPAndDatasets(ip string, datasets []string) error {
+	if ip == "" {
+		return status.Errorf(codes.InvalidArgument, "ip address is required")
+	}
+
+	if net.ParseIP(ip) == nil {
+		return status.Errorf(codes.InvalidArgument, "invalid ip address")
 	}
 
-	geo, err := v.geoIPFinder.ExposeGeolocationByIP(ctx, req.GetIp())
-	if err == nil {
-		return &api.GeolocationByIPExposerResponse{
-			IpAddress:    geo.IPAddress,
-			Country:      geo.Country,
-			CountryCode:  geo.CountryCode,
-			City:         geo.City,
-			Latitude:     geo.Latitude,
-			Longitude:    geo.Longitude,
-			MysteryValue: geo.MysteryValue,
-		}, nil
+	for _, dataset := range datasets {
+		if err := model.CheckDatasetName(dataset); err != nil {
+			return status.Errorf(codes.InvalidArgument, "invalid dataset")
+		}
 	}
 
+	return nil
+}
+
+// findError returns the status of an error finding the geolocation data.
+func findError(err error) error {
 	if errors.Is(err, model.ErrGeolocationNotFound) {
-		return nil, status.Errorf(codes.NotFound, "geolocation not found")
+		return status.Errorf(codes.NotFound, "geolocation not found")
 	}
 
-	return nil, status.Errorf(codes.Internal, "%s", err.Error())
+	return status.Errorf(codes.Internal, "%s", err.Error())
+}
+
+// geolocationResponse maps the geolocation data to the response, with the given attributes, all when none is given.
+func geolocationResponse(geo model.Geolocation, attributes ...string) *api.GeolocationByIPExposerResponse {
+	return &api.GeolocationByIPExposerResponse{
+		IpAddress:      geo.IPAddress,
+		Country:        geo.Country,
+		CountryCode:    geo.CountryCode,
+		City:           geo.City,
+		Latitude:       geo.Latitude,
+		Longitude:      geo.Longitude,
+		MysteryValue:   geo.MysteryValue,
+		Region:         geo.Region,
+		PostalCode:     geo.PostalCode,
+		TimeZone:       geo.TimeZone,
+		Asn:            geo.ASN,
+		Organization:   geo.Organization,
+		AccuracyRadius: geo.AccuracyRadius,
+		Attributes:     geo.Attributes.Select(attributes...),
+		Dataset:        geo.Dataset,
+	}
 }
@@ -0,0 +1,317 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/dohernandez/vio/pkg/database"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// geolocationRepository is the repository of the geolocation data of a backend.
+type geolocationRepository interface {
+	SaveGeolocation(ctx context.Context, geos []*model.Geolocation) error
+	FindGeolocationByIP(ctx context.Context, ip string, datasets []string) (model.Geolocation, error)
+	FindGeolocationByIPAsOf(ctx context.Context, ip string, asOf time.Time, datasets []string) (model.Geolocation, error)
+	FindGeolocationsByIP(ctx context.Context, ips []string) (map[string]model.Geolocation, error)
+	UpdateGeolocation(ctx context.Context, geos []*model.Geolocation) error
+	MergeGeolocation(ctx context.Context, added, changed []*model.Geolocation) error
+	ListGeolocation(ctx context.Context, fn func(geo model.Geolocation) error) error
+	DeleteGeolocation(ctx context.Context, ips []string) error
+	SoftDeleteGeolocation(ctx context.Context, ips []string) error
+}
+
+// datasetVersionRepository is the repository of the dataset versions of a backend.
+type datasetVersionRepository interface {
+	CreateDatasetVersion(ctx context.Context, source string) (int64, error)
+	CompleteDatasetVersion(ctx context.Context, id int64, accepted int) error
+}
+
+// backend is a storage backend under test, the repositories along with access to its tables.
+type backend struct {
+	geolocation    func(opts ...storage.Option) geolocationRepository
+	datasetVersion func(opts ...storage.Option) datasetVersionRepository
+
+	// geolocations returns the stored geolocation data of all the datasets, soft deleted excluded, ordered by dataset
+	// and IP address.
+	geolocations func(t *testing.T) []model.Geolocation
+	// history returns the history of the geolocation data, in the order it was recorded.
+	history func(t *testing.T) []storage.GeolocationHistory
+	// storeHistory stores past states of the geolocation data as they are.
+	storeHistory func(t *testing.T, history ...storage.GeolocationHistory)
+}
+
+// testBackend runs the storage tests against an empty backend for each test.
+func testBackend(t *testing.T, newBackend func(t *testing.T) backend) {
+	t.Helper()
+
+	for name, test := range map[string]func(t *testing.T, b backend){
+		"SaveGeolocation":         testSaveGeolocation,
+		"FindGeolocationByIP":     testFindGeolocationByIP,
+		"FindGeolocationByIPAsOf": testFindGeolocationByIPAsOf,
+		"UpdateGeolocation":       testUpdateGeolocation,
+		"MergeGeolocation":        testMergeGeolocation,
+		"DeleteGeolocation":       testDeleteGeolocation,
+		"DatasetVersion":          testDatasetVersion,
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			test(t, newBackend(t))
+		})
+	}
+}
+
+func testSaveGeolocation(t *testing.T, b backend) {
+	t.Helper()
+
+	ctx := context.Background()
+	s := b.geolocation()
+
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{
+		{IPAddress: "200.106.141.15", City: "Ljubljana"},
+		{IPAddress: "160.103.7.140", City: "Prague"},
+	}))
+
+	// A batch saved again, i.e. when retried, leaves the stored geolocation data as it is.
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{
+		{IPAddress: "200.106.141.15", City: "Maribor"},
+		{IPAddress: "70.95.73.73", City: "Dili"},
+	}))
+
+	var stored []string
+
+	for _, geo := range b.geolocations(t) {
+		stored = append(stored, geo.IPAddress+" "+geo.City)
+	}
+
+	assert.ElementsMatch(t, []string{"200.106.141.15 Ljubljana", "160.103.7.140 Prague", "70.95.73.73 Dili"}, stored)
+}
+
+func testFindGeolocationByIP(t *testing.T, b backend) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	maxmind := b.geolocation(storage.WithDataset("maxmind"))
+	ip2location := b.geolocation(storage.WithDataset("ip2location"))
+
+	require.NoError(t, maxmind.SaveGeolocation(ctx, []*model.Geolocation{
+		{
+			IPAddress:    "200.106.141.15",
+			CountryCode:  "SI",
+			Country:      "Slovenia",
+			City:         "Ljubljana",
+			Latitude:     46.0569,
+			Longitude:    14.5058,
+			MysteryValue: 7823011346,
+			ASN:          2108,
+			Attributes:   model.Attributes{"isp": "Telekom Slovenije"},
+			SourceFile:   "maxmind.csv",
+			SourceLine:   2,
+		},
+	}))
+	require.NoError(t, ip2location.SaveGeolocation(ctx, []*model.Geolocation{
+		{IPAddress: "200.106.141.15", CountryCode: "SI", Country: "Slovenia", City: "Maribor"},
+		{IPAddress: "::ffff:160.103.7.140", CountryCode: "CZ", Country: "Czechia", City: "Prague"},
+	}))
+
+	geo, err := maxmind.FindGeolocationByIP(ctx, "200.106.141.15", nil)
+	require.NoError(t, err)
+	assert.Equal(t, model.Geolocation{
+		IPAddress:    "200.106.141.15",
+		CountryCode:  "SI",
+		Country:      "Slovenia",
+		City:         "Ljubljana",
+		Latitude:     46.0569,
+		Longitude:    14.5058,
+		MysteryValue: 7823011346,
+		ASN:          2108,
+		Attributes:   model.Attributes{"isp": "Telekom Slovenije"},
+		Dataset:      "maxmind",
+		SourceFile:   "maxmind.csv",
+		SourceLine:   2,
+	}, geo)
+
+	geo, err = maxmind.FindGeolocationByIP(ctx, "200.106.141.15", []string{"ip2location", "maxmind"})
+	require.NoError(t, err)
+	assert.Equal(t, "Maribor", geo.City)
+
+	geo, err = maxmind.FindGeolocationByIP(ctx, "160.103.7.140", []string{"maxmind", "ip2location"})
+	require.NoError(t, err)
+	assert.Equal(t, "Prague", geo.City)
+
+	_, err = maxmind.FindGeolocationByIP(ctx, "160.103.7.140", nil)
+	require.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func testFindGeolocationByIPAsOf(t *testing.T, b backend) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	validFrom := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	validTo := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	b.storeHistory(t, storage.GeolocationHistory{
+		Geolocation: model.Geolocation{IPAddress: "200.106.141.15", City: "Ljubljana", Dataset: model.DefaultDataset},
+		ValidFrom:   validFrom,
+		ValidTo:     &validTo,
+	})
+
+	s := b.geolocation()
+
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{{IPAddress: "200.106.141.15", City: "Maribor"}}))
+
+	geo, err := s.FindGeolocationByIPAsOf(ctx, "200.106.141.15", time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Ljubljana", geo.City)
+
+	geo, err = s.FindGeolocationByIPAsOf(ctx, "200.106.141.15", time.Now().Add(time.Minute), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Maribor", geo.City)
+
+	_, err = s.FindGeolocationByIPAsOf(ctx, "200.106.141.15", validTo, nil)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	_, err = s.FindGeolocationByIPAsOf(ctx, "200.106.141.15", time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), nil)
+	require.ErrorIs(t, err, database.ErrNotFound)
+}
+
+func testMergeGeolocation(t *testing.T, b backend) {
+	t.Helper()
+
+	ctx := context.Background()
+	s := b.geolocation()
+
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{{IPAddress: "200.106.141.15", City: "Ljubljana"}}))
+
+	require.NoError(t, s.MergeGeolocation(ctx,
+		[]*model.Geolocation{{IPAddress: "160.103.7.140", City: "Prague"}},
+		[]*model.Geolocation{{IPAddress: "200.106.141.15", City: "Maribor"}},
+	))
+
+	geos, err := s.FindGeolocationsByIP(ctx, []string{"200.106.141.15", "160.103.7.140"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.Geolocation{
+		"200.106.141.15": {IPAddress: "200.106.141.15", City: "Maribor", Dataset: model.DefaultDataset},
+		"160.103.7.140":  {IPAddress: "160.103.7.140", City: "Prague", Dataset: model.DefaultDataset},
+	}, geos)
+
+	// Nothing to merge.
+	require.NoError(t, s.MergeGeolocation(ctx, nil, nil))
+}
+
+func testUpdateGeolocation(t *testing.T, b backend) {
+	t.Helper()
+
+	ctx := context.Background()
+	s := b.geolocation()
+
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{
+		{IPAddress: "200.106.141.15", City: "Ljubljana", Attributes: model.Attributes{"isp": "Telekom"}},
+		{IPAddress: "160.103.7.140", City: "Prague"},
+	}))
+
+	require.NoError(t, s.UpdateGeolocation(ctx, []*model.Geolocation{
+		{IPAddress: "200.106.141.15", City: "Maribor"},
+		{IPAddress: "70.95.73.73", City: "Dili"},
+	}))
+
+	geos, err := s.FindGeolocationsByIP(ctx, []string{"200.106.141.15", "70.95.73.73"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]model.Geolocation{
+		"200.106.141.15": {IPAddress: "200.106.141.15", City: "Maribor", Dataset: model.DefaultDataset},
+	}, geos)
+
+	var listed []string
+
+	require.NoError(t, s.ListGeolocation(ctx, func(geo model.Geolocation) error {
+		listed = append(listed, geo.IPAddress+" "+geo.City)
+
+		return nil
+	}))
+	assert.ElementsMatch(t, []string{"160.103.7.140 Prague", "200.106.141.15 Maribor"}, listed)
+
+	var history []storage.GeolocationHistory
+
+	for _, h := range b.history(t) {
+		if h.IPAddress == "200.106.141.15" {
+			history = append(history, h)
+		}
+	}
+
+	require.Len(t, history, 2)
+	assert.Equal(t, "Ljubljana", history[0].City)
+	assert.NotNil(t, history[0].ValidTo)
+	assert.Equal(t, "Maribor", history[1].City)
+	assert.Nil(t, history[1].ValidTo)
+}
+
+func testDeleteGeolocation(t *testing.T, b backend) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	s := b.geolocation()
+	other := b.geolocation(storage.WithDataset("maxmind"))
+
+	geos := func() []*model.Geolocation {
+		return []*model.Geolocation{
+			{IPAddress: "200.106.141.15", City: "Ljubljana"},
+			{IPAddress: "160.103.7.140", City: "Prague"},
+		}
+	}
+
+	require.NoError(t, s.SaveGeolocation(ctx, geos()))
+	require.NoError(t, other.SaveGeolocation(ctx, geos()))
+
+	require.NoError(t, s.DeleteGeolocation(ctx, []string{"200.106.141.15"}))
+	require.NoError(t, s.SoftDeleteGeolocation(ctx, []string{"160.103.7.140"}))
+
+	_, err := s.FindGeolocationByIP(ctx, "200.106.141.15", nil)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	_, err = s.FindGeolocationByIP(ctx, "160.103.7.140", nil)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	stored := b.geolocations(t)
+	require.Len(t, stored, 2)
+	assert.Equal(t, "maxmind", stored[0].Dataset)
+	assert.Equal(t, "maxmind", stored[1].Dataset)
+
+	for _, h := range b.history(t) {
+		assert.Equal(t, h.Dataset == model.DefaultDataset, h.ValidTo != nil, h.Dataset+" "+h.IPAddress)
+	}
+
+	// The soft deleted IP address found again in a later feed is stored again.
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{{IPAddress: "160.103.7.140", City: "Brno"}}))
+
+	geo, err := s.FindGeolocationByIP(ctx, "160.103.7.140", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Brno", geo.City)
+
+	assert.Len(t, b.geolocations(t), 3)
+}
+
+func testDatasetVersion(t *testing.T, b backend) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	s := b.datasetVersion(storage.WithDataset("maxmind"))
+
+	id, err := s.CreateDatasetVersion(ctx, "data_dump.csv")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+
+	require.NoError(t, s.CompleteDatasetVersion(ctx, id, 4))
+
+	id, err = b.datasetVersion().CreateDatasetVersion(ctx, "data_dump.csv")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), id)
+}
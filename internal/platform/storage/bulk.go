@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"context"
+	"net/netip"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/bool64/ctxd"
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GeolocationBulkIndexTable is the table keeping the definition of the indexes of GeolocationTable dropped by a bulk
+// load.
+const GeolocationBulkIndexTable = "geolocation_bulk_index"
+
+// GeolocationIndex is an index of GeolocationTable, along with the statement creating it.
+type GeolocationIndex struct {
+	Name       string `db:"name"`
+	Definition string `db:"definition"`
+}
+
+// GeolocationIndexes returns the indexes of GeolocationTable, constraints and unique indexes excluded, ordered by name.
+func (s *Geolocation) GeolocationIndexes(ctx context.Context) ([]GeolocationIndex, error) {
+	errMsg := "storage.Geolocation: failed to list the indexes"
+
+	var (
+		indexes []GeolocationIndex
+		q       squirrel.SelectBuilder
+	)
+
+	if s.storage.Mapper != nil && s.storage.Mapper.Dialect == sqluct.DialectSQLite3 {
+		q = s.storage.QueryBuilder().
+			Select("name", "sql AS definition").
+			From("sqlite_master").
+			Where(squirrel.Eq{"type": "index", "tbl_name": GeolocationTable}).
+			Where("sql IS NOT NULL").
+			Where("sql NOT LIKE 'CREATE UNIQUE %'").
+			OrderBy("name")
+	} else {
+		q = s.storage.QueryBuilder().
+			Select("indexname AS name", "indexdef AS definition").
+			From("pg_indexes").
+			Where(squirrel.Eq{"tablename": GeolocationTable}).
+			Where("schemaname = current_schema()").
+			Where("indexname NOT IN (SELECT conname FROM pg_constraint WHERE conrelid = ?::regclass)", GeolocationTable).
+			OrderBy("indexname")
+	}
+
+	if err := s.storage.Select(ctx, q, &indexes); err != nil {
+		return nil, ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return indexes, nil
+}
+
+// DropGeolocationIndexes drops the indexes of GeolocationTable ahead of a bulk load, keeping their definition in
+// GeolocationBulkIndexTable to recreate them with RestoreGeolocationIndexes.
+//
+// Indexes are table wide, the ones of all the datasets are dropped. The definitions kept by a bulk load which did not
+// restore them are left as they are.
+func (s *Geolocation) DropGeolocationIndexes(ctx context.Context) error {
+	errMsg := "storage.Geolocation: failed to drop the indexes"
+
+	return s.storage.InTx(ctx, func(ctx context.Context) error {
+		indexes, err := s.GeolocationIndexes(ctx)
+		if err != nil {
+			return err
+		}
+
+		for _, idx := range indexes {
+			q := s.storage.InsertStmt(GeolocationBulkIndexTable, idx).Suffix("ON CONFLICT (name) DO NOTHING")
+
+			if _, err := s.storage.Exec(ctx, q); err != nil {
+				return ctxd.WrapError(ctx, err, errMsg, "index", idx.Name)
+			}
+
+			if _, err := s.storage.Exec(ctx, squirrel.Expr("DROP INDEX IF EXISTS "+quoteIdent(idx.Name))); err != nil {
+				return ctxd.WrapError(ctx, err, errMsg, "index", idx.Name)
+			}
+		}
+
+		return nil
+	})
+}
+
+// RestoreGeolocationIndexes recreates the indexes of GeolocationTable dropped by DropGeolocationIndexes, the
+// definitions kept are removed as each index is recreated.
+//
+// The indexes of the partitioned GeolocationTable are recreated on its partitions as well, see partitionedDefinition.
+func (s *Geolocation) RestoreGeolocationIndexes(ctx context.Context) error {
+	errMsg := "storage.Geolocation: failed to restore the indexes"
+
+	var dropped []GeolocationIndex
+
+	q := s.storage.SelectStmt(GeolocationBulkIndexTable, GeolocationIndex{}).OrderBy("name")
+
+	if err := s.storage.Select(ctx, q, &dropped); err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	existing, err := s.GeolocationIndexes(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, idx := range dropped {
+		err := s.storage.InTx(ctx, func(ctx context.Context) error {
+			exists := slices.ContainsFunc(existing, func(e GeolocationIndex) bool { return e.Name == idx.Name })
+
+			if !exists {
+				if _, err := s.storage.Exec(ctx, squirrel.Expr(partitionedDefinition(idx.Definition))); err != nil {
+					return err
+				}
+			}
+
+			q := s.storage.DeleteStmt(GeolocationBulkIndexTable).Where(squirrel.Eq{"name": idx.Name})
+
+			_, err := s.storage.Exec(ctx, q)
+
+			return err
+		})
+		if err != nil {
+			return ctxd.WrapError(ctx, err, errMsg, "index", idx.Name)
+		}
+	}
+
+	return nil
+}
+
+// partitionedDefinition returns the index definition creating the index on the partitions of the table as well.
+//
+// Postgres defines the index of a partitioned table ON ONLY the table, which creates an invalid index until one is
+// attached per partition. Dropping the index drops the ones of the partitions, so the definition has to recreate them.
+func partitionedDefinition(definition string) string {
+	return strings.Replace(definition, " ON ONLY ", " ON ", 1)
+}
+
+// quoteIdent quotes the identifier, as Postgres and SQLite do.
+func quoteIdent(name string) string {
+	return pgx.Identifier{name}.Sanitize()
+}
+
+// GeolocationCopier represents a GeolocationDataStorage saving the geolocation data of its dataset with COPY, the
+// fastest way to bulk load Postgres, see WithDataset.
+type GeolocationCopier struct {
+	pool    *pgxpool.Pool
+	mapper  *sqluct.Mapper
+	dataset string
+}
+
+// NewGeolocationCopier returns instance of GeolocationCopier.
+func NewGeolocationCopier(pool *pgxpool.Pool, opts ...Option) *GeolocationCopier {
+	return &GeolocationCopier{
+		pool:    pool,
+		mapper:  &sqluct.Mapper{},
+		dataset: newOptions(opts).dataset,
+	}
+}
+
+// SaveGeolocation copies the geolocation data to the dataset of the repository, replacing the soft deleted geolocation
+// data of its IP addresses.
+func (s *GeolocationCopier) SaveGeolocation(ctx context.Context, geos []*model.Geolocation) error {
+	errMsg := "storage.GeolocationCopier: failed to copy Geolocation"
+
+	if len(geos) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, 0, len(geos))
+	ips := make([]string, 0, len(geos))
+
+	var columns []string
+
+	for _, geo := range geos {
+		geo.Dataset = s.dataset
+
+		cols, values := s.mapper.ColumnsValues(reflect.ValueOf(*geo))
+
+		addr, err := netip.ParseAddr(geo.IPAddress)
+		if err != nil {
+			return ctxd.WrapError(ctx, err, errMsg, "ip_address", geo.IPAddress)
+		}
+
+		// inet is copied in binary, from an address.
+		values[slices.Index(cols, "ip_address")] = addr
+
+		columns = cols
+		rows = append(rows, values)
+		ips = append(ips, geo.IPAddress)
+	}
+
+	err := pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		// The soft deleted geolocation data of an IP address found again is purged, as Geolocation.SaveGeolocation does.
+		if _, err := tx.Exec(ctx,
+			"DELETE FROM "+quoteIdent(GeolocationTable)+
+				" WHERE dataset = $1 AND ip_address = ANY($2::inet[]) AND deleted_at IS NOT NULL",
+			s.dataset, ips,
+		); err != nil {
+			return err
+		}
+
+		_, err := tx.CopyFrom(ctx, pgx.Identifier{GeolocationTable}, columns, pgx.CopyFromRows(rows))
+
+		return err
+	})
+	if err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/platform/config"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	_ "github.com/jackc/pgx/v5/stdlib" // Postgres driver
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newPostgresStorage returns the storage of a schema of its own in the Postgres database of the integration tests,
+// migrated, dropped once the test ends. The test is skipped when the database is not running.
+func newPostgresStorage(t *testing.T) *sqluct.Storage {
+	t.Helper()
+
+	require.NoError(t, config.WithEnvFiles("../../../.env.integration-test"))
+
+	dsn := os.Getenv("DATABASE_DSN")
+	if !strings.HasPrefix(dsn, "postgres") {
+		t.Skip("DATABASE_DSN is not a Postgres database")
+	}
+
+	ctx := context.Background()
+	schema := fmt.Sprintf("vio_test_%d", time.Now().UnixNano())
+
+	admin, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+
+	if err := admin.PingContext(ctx); err != nil {
+		assert.NoError(t, admin.Close())
+		t.Skipf("Postgres database not available: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_, err := admin.ExecContext(ctx, "DROP SCHEMA IF EXISTS "+schema+" CASCADE")
+		assert.NoError(t, err)
+		assert.NoError(t, admin.Close())
+	})
+
+	_, err = admin.ExecContext(ctx, "CREATE SCHEMA "+schema)
+	require.NoError(t, err)
+
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+
+	dsn += sep + "search_path=" + schema
+
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+
+	m, err := storage.NewMigrator(db, sqluct.DialectPostgres)
+	require.NoError(t, err)
+	require.NoError(t, m.Up(ctx))
+	require.NoError(t, m.Close())
+
+	dbx, err := sqlx.Open("pgx", dsn)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		assert.NoError(t, dbx.Close())
+	})
+
+	st := sqluct.NewStorage(dbx)
+	st.Format = squirrel.Dollar
+
+	return st
+}
+
+// partitionIndex is an index of GeolocationTable or of one of its partitions.
+type partitionIndex struct {
+	Table string `db:"table_name"`
+	Name  string `db:"index_name"`
+	Valid bool   `db:"valid"`
+}
+
+// partitionIndexes returns the indexes of GeolocationTable and of its partitions, constraints excluded.
+func partitionIndexes(t *testing.T, st *sqluct.Storage) []partitionIndex {
+	t.Helper()
+
+	var indexes []partitionIndex
+
+	q := squirrel.Select("t.relname AS table_name", "c.relname AS index_name", "i.indisvalid AS valid").
+		From("pg_index i").
+		Join("pg_class c ON c.oid = i.indexrelid").
+		Join("pg_class t ON t.oid = i.indrelid").
+		Where("t.relnamespace = current_schema()::regnamespace").
+		Where(squirrel.Eq{"t.relname": []string{"geolocation", "geolocation_ipv4", "geolocation_ipv6"}}).
+		Where("NOT i.indisprimary AND NOT i.indisunique").
+		OrderBy("t.relname")
+
+	require.NoError(t, st.Select(context.Background(), q, &indexes))
+
+	return indexes
+}
+
+func TestGeolocation_bulkIndexes_postgres(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping test in short mode.")
+	}
+
+	ctx := context.Background()
+	st := newPostgresStorage(t)
+	s := storage.NewGeolocation(st)
+
+	before := partitionIndexes(t, st)
+	require.Len(t, before, 3, "index of the table and of each partition")
+
+	require.NoError(t, s.DropGeolocationIndexes(ctx))
+	assert.Empty(t, partitionIndexes(t, st))
+
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{
+		{IPAddress: "200.106.141.15", City: "Ljubljana"},
+		{IPAddress: "2001:db8::1", City: "Maribor"},
+	}))
+
+	require.NoError(t, s.RestoreGeolocationIndexes(ctx))
+
+	after := partitionIndexes(t, st)
+	require.Len(t, after, 3)
+
+	for _, idx := range after {
+		assert.True(t, idx.Valid, idx.Name)
+	}
+
+	geo, err := s.FindGeolocationByIP(ctx, "2001:db8::1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Maribor", geo.City)
+}
@@ -0,0 +1,111 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeolocation_DropGeolocationIndexes(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	// The index of the partitioned table, as defined by Postgres.
+	definition := "CREATE INDEX geolocation_dataset_ip_address_idx ON ONLY public.geolocation USING btree (dataset, ip_address)"
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`SELECT indexname AS name, indexdef AS definition FROM pg_indexes `+
+		`WHERE tablename = $1 AND schemaname = current_schema() `+
+		`AND indexname NOT IN (SELECT conname FROM pg_constraint WHERE conrelid = $2::regclass) ORDER BY indexname`).
+		WithArgs(storage.GeolocationTable, storage.GeolocationTable).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "definition"}).
+			AddRow("geolocation_dataset_ip_address_idx", definition))
+	mock.ExpectExec(`INSERT INTO geolocation_bulk_index (name,definition) VALUES ($1,$2) ON CONFLICT (name) DO NOTHING`).
+		WithArgs("geolocation_dataset_ip_address_idx", definition).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DROP INDEX IF EXISTS "geolocation_dataset_ip_address_idx"`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	require.NoError(t, storage.NewGeolocation(st).DropGeolocationIndexes(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_RestoreGeolocationIndexes(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	mock.ExpectQuery(`SELECT name, definition FROM geolocation_bulk_index ORDER BY name`).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "definition"}).
+			AddRow("geolocation_dataset_ip_address_idx",
+				"CREATE INDEX geolocation_dataset_ip_address_idx ON ONLY public.geolocation USING btree (dataset, ip_address)"))
+	mock.ExpectQuery(`SELECT indexname AS name, indexdef AS definition FROM pg_indexes `+
+		`WHERE tablename = $1 AND schemaname = current_schema() `+
+		`AND indexname NOT IN (SELECT conname FROM pg_constraint WHERE conrelid = $2::regclass) ORDER BY indexname`).
+		WithArgs(storage.GeolocationTable, storage.GeolocationTable).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "definition"}))
+	mock.ExpectBegin()
+	// The index is recreated on the partitions as well.
+	mock.ExpectExec(`CREATE INDEX geolocation_dataset_ip_address_idx ON public.geolocation USING btree (dataset, ip_address)`).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`DELETE FROM geolocation_bulk_index WHERE name = $1`).
+		WithArgs("geolocation_dataset_ip_address_idx").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	require.NoError(t, storage.NewGeolocation(st).RestoreGeolocationIndexes(context.Background()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_bulkIndexes_sqlite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := storage.NewGeolocation(newSQLiteStorage(t))
+
+	indexes, err := s.GeolocationIndexes(ctx)
+	require.NoError(t, err)
+	require.Len(t, indexes, 1)
+	assert.Equal(t, "geolocation_dataset_ip_address_idx", indexes[0].Name)
+
+	require.NoError(t, s.DropGeolocationIndexes(ctx))
+
+	dropped, err := s.GeolocationIndexes(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, dropped)
+
+	// A bulk load not restoring the indexes leaves their definition for the next one.
+	require.NoError(t, s.DropGeolocationIndexes(ctx))
+
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{{IPAddress: "200.106.141.15", City: "Ljubljana"}}))
+
+	require.NoError(t, s.RestoreGeolocationIndexes(ctx))
+
+	restored, err := s.GeolocationIndexes(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, indexes, restored)
+
+	// Nothing left to restore.
+	require.NoError(t, s.RestoreGeolocationIndexes(ctx))
+
+	geo, err := s.FindGeolocationByIP(ctx, "200.106.141.15", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Ljubljana", geo.City)
+}
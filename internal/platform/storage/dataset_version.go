@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/bool64/ctxd"
+	"github.com/bool64/sqluct"
+)
+
+// DatasetVersionTable is the table name for dataset version.
+const DatasetVersionTable = "dataset_version"
+
+// DatasetVersion represents a DatasetVersion repository.
+type DatasetVersion struct {
+	storage *sqluct.Storage
+	dataset string
+}
+
+// NewDatasetVersion returns instance of DatasetVersion repository, versioning the dataset set up with WithDataset.
+func NewDatasetVersion(storage *sqluct.Storage, opts ...Option) *DatasetVersion {
+	return &DatasetVersion{
+		storage: storage,
+		dataset: newOptions(opts).dataset,
+	}
+}
+
+// CreateDatasetVersion creates the dataset version of the import of the source.
+//
+// Returns the ID of the version.
+func (s *DatasetVersion) CreateDatasetVersion(ctx context.Context, source string) (int64, error) {
+	errMsg := "storage.DatasetVersion: failed to create DatasetVersion"
+
+	q := s.storage.QueryBuilder().
+		Insert(DatasetVersionTable).
+		Columns(colDataset, "source").
+		Values(s.dataset, source).
+		Suffix("RETURNING id")
+
+	var id int64
+
+	if err := s.storage.Select(ctx, q, &id); err != nil {
+		return 0, ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return id, nil
+}
+
+// CompleteDatasetVersion marks the dataset version as completed, with the number of geolocation data accepted.
+func (s *DatasetVersion) CompleteDatasetVersion(ctx context.Context, id int64, accepted int) error {
+	errMsg := "storage.DatasetVersion: failed to complete DatasetVersion"
+
+	q := s.storage.QueryBuilder().
+		Update(DatasetVersionTable).
+		Set("accepted", accepted).
+		Set("completed_at", squirrel.Expr("CURRENT_TIMESTAMP")).
+		Where(squirrel.Eq{"id": id})
+
+	if _, err := s.storage.Exec(ctx, q); err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return nil
+}
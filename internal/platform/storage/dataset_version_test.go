@@ -0,0 +1,54 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetVersion_CreateDatasetVersion(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	mock.ExpectQuery(`INSERT INTO dataset_version (dataset,source) VALUES ($1,$2) RETURNING id`).
+		WithArgs("maxmind", "data_dump.csv").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(42))
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewDatasetVersion(st, storage.WithDataset("maxmind"))
+
+	id, err := s.CreateDatasetVersion(context.Background(), "data_dump.csv")
+	require.NoError(t, err)
+	require.Equal(t, int64(42), id)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDatasetVersion_CompleteDatasetVersion(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	mock.ExpectExec(`UPDATE dataset_version SET accepted = $1, completed_at = CURRENT_TIMESTAMP WHERE id = $2`).
+		WithArgs(4, int64(42)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewDatasetVersion(st)
+
+	require.NoError(t, s.CompleteDatasetVersion(context.Background(), 42, 4))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -2,6 +2,10 @@ package storage
 
 import (
 	"context"
+	"net/netip"
+	"reflect"
+	"slices"
+	"time"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/bool64/ctxd"
@@ -14,30 +18,123 @@ import (
 // GeolocationTable is the table name for geolocation.
 const GeolocationTable = "geolocation"
 
+// GeolocationHistoryTable is the table name for the history of geolocation, kept by a trigger of GeolocationTable.
+const GeolocationHistoryTable = "geolocation_history"
+
+const (
+	// colUpdatedAt is the column keeping the last time a geolocation was updated.
+	colUpdatedAt = "updated_at"
+	// colDeletedAt is the column keeping the time a geolocation was soft deleted.
+	colDeletedAt = "deleted_at"
+	// colValidFrom and colValidTo are the columns of the history keeping the time range a geolocation was valid,
+	// valid_to excluded.
+	colValidFrom = "valid_from"
+	colValidTo   = "valid_to"
+)
+
+const (
+	// postgresMaxParams is the Postgres limit of bind parameters per statement.
+	postgresMaxParams = 65535
+	// sqliteMaxParams is the SQLite limit of bind parameters per statement, SQLITE_MAX_VARIABLE_NUMBER.
+	sqliteMaxParams = 32766
+)
+
+// notDeleted filters out the soft deleted geolocation.
+var notDeleted = squirrel.Eq{colDeletedAt: nil}
+
+// GeolocationHistory is a state of the geolocation data, as kept in GeolocationHistoryTable.
+type GeolocationHistory struct {
+	model.Geolocation
+
+	ValidFrom time.Time `db:"valid_from"`
+	// ValidTo is nil for the current state.
+	ValidTo *time.Time `db:"valid_to"`
+}
+
 // Geolocation represents a Geolocation repository.
+//
+// The repository writes and lists the geolocation data of its dataset, see WithDataset. Lookups by IP can choose the
+// datasets, and run on the read replicas set up with WithReplicas.
 type Geolocation struct {
-	storage *sqluct.Storage
+	storage  *sqluct.Storage
+	dataset  string
+	replicas *Replicas
 
 	colIPAddress string
+	maxBatchSize int
 }
 
 // NewGeolocation returns instance of Geolocation repository.
-func NewGeolocation(storage *sqluct.Storage) *Geolocation {
+func NewGeolocation(storage *sqluct.Storage, opts ...Option) *Geolocation {
 	var geoLocation model.Geolocation
 
+	o := newOptions(opts)
+
 	return &Geolocation{
 		storage:      storage,
+		dataset:      o.dataset,
+		replicas:     o.replicas,
 		colIPAddress: storage.Mapper.Col(&geoLocation, &geoLocation.IPAddress),
+		maxBatchSize: maxInsertRows(storage),
+	}
+}
+
+// maxInsertRows returns the maximum number of geolocation data inserted in a single statement, one bind parameter per
+// column of each row, within the limit of the database.
+func maxInsertRows(storage *sqluct.Storage) int {
+	columns, _ := storage.Mapper.ColumnsValues(reflect.ValueOf(model.Geolocation{}))
+
+	return maxParams(storage) / len(columns)
+}
+
+// maxParams returns the limit of bind parameters per statement of the database.
+func maxParams(storage *sqluct.Storage) int {
+	if storage.Mapper != nil && storage.Mapper.Dialect == sqluct.DialectSQLite3 {
+		return sqliteMaxParams
 	}
+
+	return postgresMaxParams
 }
 
-// SaveGeolocation store the geolocation data.
+// MaxBatchSize returns the maximum number of geolocation data SaveGeolocation saves at once.
+func (s *Geolocation) MaxBatchSize() int {
+	return s.maxBatchSize
+}
+
+// inDataset filters the geolocation data of the dataset of the repository.
+func (s *Geolocation) inDataset() squirrel.Eq {
+	return squirrel.Eq{colDataset: s.dataset}
+}
+
+// SaveGeolocation store the geolocation data in the dataset of the repository.
+//
+// The geolocation data of an IP address already stored in the dataset is left as it is, so that saving a batch again
+// does not store it twice. The soft deleted geolocation data of the IP address is replaced.
 func (s *Geolocation) SaveGeolocation(ctx context.Context, geos []*model.Geolocation) error {
 	errMsg := "storage.Geolocation: failed to save Geolocation"
 
-	q := s.storage.InsertStmt(GeolocationTable, geos)
+	ips := make([]string, 0, len(geos))
+
+	for _, geo := range geos {
+		geo.Dataset = s.dataset
 
-	_, err := s.storage.Exec(ctx, q)
+		ips = append(ips, geo.IPAddress)
+	}
+
+	// The unique indexes on dataset and IP address are on the partitions of GeolocationTable, none on the table can be
+	// the conflict target. Without a target, the conflicts on any unique index of the partition are skipped.
+	q := s.storage.InsertStmt(GeolocationTable, geos).Suffix("ON CONFLICT DO NOTHING")
+
+	err := s.storage.InTx(ctx, func(ctx context.Context) error {
+		// The soft deleted geolocation data of an IP address found again is purged, for the IP address to be stored.
+		if err := s.purgeDeleted(ctx, ips); err != nil {
+			return err
+		}
+
+		_, err := s.storage.Exec(ctx, q)
+
+		return err
+	})
 	if err == nil {
 		return nil
 	}
@@ -49,23 +146,304 @@ func (s *Geolocation) SaveGeolocation(ctx context.Context, geos []*model.Geoloca
 	return ctxd.WrapError(ctx, err, errMsg)
 }
 
-// FindGeolocationByIP get the geolocation data by IP.
-func (s *Geolocation) FindGeolocationByIP(ctx context.Context, ip string) (model.Geolocation, error) {
+// FindGeolocationByIP get the geolocation data by IP from the first of the datasets having it, in order. The dataset
+// of the repository when no dataset is given.
+//
+// The IP address is canonicalised, so any text form of the address is found. IPv4 addresses are also looked up as
+// IPv4-mapped IPv6, to find the geolocation data stored with the model.KeepMappedIPv4 policy.
+func (s *Geolocation) FindGeolocationByIP(ctx context.Context, ip string, datasets []string) (model.Geolocation, error) {
 	errMsg := "storage.Geolocation: failed to get Geolocation by IP"
 
-	var geo model.Geolocation
+	datasets = s.lookupDatasets(datasets)
 
-	q := s.storage.SelectStmt(GeolocationTable, geo).
-		Where(squirrel.Eq{s.colIPAddress: ip})
+	var geos []model.Geolocation
 
-	err := s.storage.Select(ctx, q, &geo)
+	q := s.storage.SelectStmt(GeolocationTable, model.Geolocation{}).
+		Where(squirrel.Eq{s.colIPAddress: ipAddressForms(ip)}).
+		Where(squirrel.Eq{colDataset: datasets}).
+		Where(notDeleted)
+
+	err := s.replicas.Read(ctx, s.storage, func(st *sqluct.Storage) error {
+		return st.Select(ctx, q, &geos)
+	})
 	if err != nil {
-		if pgx.IsNoRows(err) {
-			return geo, ctxd.WrapError(ctx, database.ErrNotFound, errMsg)
-		}
+		return model.Geolocation{}, ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	geo, ok := firstByDataset(geos, datasets)
+	if !ok {
+		return geo, ctxd.WrapError(ctx, database.ErrNotFound, errMsg)
+	}
+
+	return geo, nil
+}
+
+// FindGeolocationByIPAsOf get the geolocation data by IP as it was at the given time.
+//
+// The IP address and the datasets are looked up as in FindGeolocationByIP.
+func (s *Geolocation) FindGeolocationByIPAsOf(
+	ctx context.Context,
+	ip string,
+	asOf time.Time,
+	datasets []string,
+) (model.Geolocation, error) {
+	errMsg := "storage.Geolocation: failed to get Geolocation by IP as of"
+
+	datasets = s.lookupDatasets(datasets)
+	at := timeArg(s.storage, asOf)
+
+	var geos []model.Geolocation
+
+	q := s.storage.SelectStmt(GeolocationHistoryTable, model.Geolocation{}).
+		Where(squirrel.Eq{s.colIPAddress: ipAddressForms(ip)}).
+		Where(squirrel.Eq{colDataset: datasets}).
+		Where(squirrel.LtOrEq{colValidFrom: at}).
+		Where(squirrel.Or{squirrel.Eq{colValidTo: nil}, squirrel.Gt{colValidTo: at}}).
+		OrderBy(colValidFrom + " DESC")
+
+	err := s.replicas.Read(ctx, s.storage, func(st *sqluct.Storage) error {
+		return st.Select(ctx, q, &geos)
+	})
+	if err != nil {
+		return model.Geolocation{}, ctxd.WrapError(ctx, err, errMsg)
+	}
 
-		return geo, ctxd.WrapError(ctx, err, errMsg)
+	geo, ok := firstByDataset(geos, datasets)
+	if !ok {
+		return geo, ctxd.WrapError(ctx, database.ErrNotFound, errMsg)
 	}
 
 	return geo, nil
 }
+
+// lookupDatasets returns the datasets to look up, the dataset of the repository when none is given.
+func (s *Geolocation) lookupDatasets(datasets []string) []string {
+	if len(datasets) == 0 {
+		return []string{s.dataset}
+	}
+
+	return datasets
+}
+
+// firstByDataset returns the first geolocation data of the first dataset having any.
+func firstByDataset(geos []model.Geolocation, datasets []string) (model.Geolocation, bool) {
+	for _, dataset := range datasets {
+		for _, geo := range geos {
+			if geo.Dataset == dataset {
+				return geo, true
+			}
+		}
+	}
+
+	return model.Geolocation{}, false
+}
+
+// FindGeolocationsByIP get the geolocation data of the given IPs in the dataset of the repository, indexed by IP.
+func (s *Geolocation) FindGeolocationsByIP(ctx context.Context, ips []string) (map[string]model.Geolocation, error) {
+	errMsg := "storage.Geolocation: failed to get Geolocations by IP"
+
+	var geos []model.Geolocation
+
+	q := s.storage.SelectStmt(GeolocationTable, model.Geolocation{}).
+		Where(squirrel.Eq{s.colIPAddress: ips}).
+		Where(s.inDataset()).
+		Where(notDeleted)
+
+	if err := s.storage.Select(ctx, q, &geos); err != nil {
+		return nil, ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	res := make(map[string]model.Geolocation, len(geos))
+
+	for _, geo := range geos {
+		res[geo.IPAddress] = geo
+	}
+
+	return res, nil
+}
+
+// UpdateGeolocation updates the geolocation data by IP in the dataset of the repository.
+func (s *Geolocation) UpdateGeolocation(ctx context.Context, geos []*model.Geolocation) error {
+	errMsg := "storage.Geolocation: failed to update Geolocation"
+
+	err := s.storage.InTx(ctx, func(ctx context.Context) error {
+		for _, geo := range geos {
+			geo.Dataset = s.dataset
+
+			q := s.storage.UpdateStmt(GeolocationTable, geo).
+				Set(colUpdatedAt, squirrel.Expr("CURRENT_TIMESTAMP")).
+				Where(squirrel.Eq{s.colIPAddress: geo.IPAddress}).
+				Where(s.inDataset()).
+				Where(notDeleted)
+
+			if _, err := s.storage.Exec(ctx, q); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return nil
+}
+
+// MergeGeolocation saves the new geolocation data and updates the changed one in the dataset of the repository, in a
+// single transaction.
+func (s *Geolocation) MergeGeolocation(ctx context.Context, added, changed []*model.Geolocation) error {
+	return s.storage.InTx(ctx, func(ctx context.Context) error {
+		if len(added) > 0 {
+			if err := s.SaveGeolocation(ctx, added); err != nil {
+				return err
+			}
+		}
+
+		if len(changed) > 0 {
+			return s.UpdateGeolocation(ctx, changed)
+		}
+
+		return nil
+	})
+}
+
+// ListGeolocation calls fn for each stored geolocation data of the dataset of the repository.
+func (s *Geolocation) ListGeolocation(ctx context.Context, fn func(geo model.Geolocation) error) error {
+	errMsg := "storage.Geolocation: failed to list Geolocation"
+
+	q := s.storage.SelectStmt(GeolocationTable, model.Geolocation{}).
+		Where(s.inDataset()).
+		Where(notDeleted)
+
+	rows, err := s.storage.Query(ctx, q)
+	if err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	defer rows.Close() //nolint:errcheck
+
+	for rows.Next() {
+		var geo model.Geolocation
+
+		if err := rows.StructScan(&geo); err != nil {
+			return ctxd.WrapError(ctx, err, errMsg)
+		}
+
+		if err := fn(geo); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return nil
+}
+
+// DeleteGeolocation deletes the geolocation data by IP from the dataset of the repository, in a single transaction.
+func (s *Geolocation) DeleteGeolocation(ctx context.Context, ips []string) error {
+	errMsg := "storage.Geolocation: failed to delete Geolocation"
+
+	err := s.inChunks(ctx, ips, func(ctx context.Context, ips []string) error {
+		q := s.storage.DeleteStmt(GeolocationTable).
+			Where(squirrel.Eq{s.colIPAddress: ips}).
+			Where(s.inDataset())
+
+		_, err := s.storage.Exec(ctx, q)
+
+		return err
+	})
+	if err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return nil
+}
+
+// SoftDeleteGeolocation marks the geolocation data of the dataset of the repository as deleted by IP, in a single
+// transaction.
+//
+// Soft deleted geolocation data is no longer found.
+func (s *Geolocation) SoftDeleteGeolocation(ctx context.Context, ips []string) error {
+	errMsg := "storage.Geolocation: failed to soft delete Geolocation"
+
+	err := s.inChunks(ctx, ips, func(ctx context.Context, ips []string) error {
+		q := s.storage.QueryBuilder().
+			Update(GeolocationTable).
+			Set(colDeletedAt, squirrel.Expr("CURRENT_TIMESTAMP")).
+			Set(colUpdatedAt, squirrel.Expr("CURRENT_TIMESTAMP")).
+			Where(squirrel.Eq{s.colIPAddress: ips}).
+			Where(s.inDataset()).
+			Where(notDeleted)
+
+		_, err := s.storage.Exec(ctx, q)
+
+		return err
+	})
+	if err != nil {
+		return ctxd.WrapError(ctx, err, errMsg)
+	}
+
+	return nil
+}
+
+// purgeDeleted deletes the soft deleted geolocation data by IP from the dataset of the repository.
+func (s *Geolocation) purgeDeleted(ctx context.Context, ips []string) error {
+	return s.inChunks(ctx, ips, func(ctx context.Context, ips []string) error {
+		q := s.storage.DeleteStmt(GeolocationTable).
+			Where(squirrel.Eq{s.colIPAddress: ips}).
+			Where(s.inDataset()).
+			Where(squirrel.NotEq{colDeletedAt: nil})
+
+		_, err := s.storage.Exec(ctx, q)
+
+		return err
+	})
+}
+
+// inChunks calls fn with the IP addresses in chunks fitting the bind parameters of a statement, along with the
+// dataset, all of them in a single transaction.
+func (s *Geolocation) inChunks(ctx context.Context, ips []string, fn func(ctx context.Context, ips []string) error) error {
+	if len(ips) == 0 {
+		return nil
+	}
+
+	return s.storage.InTx(ctx, func(ctx context.Context) error {
+		for chunk := range slices.Chunk(ips, maxParams(s.storage)-1) {
+			if err := fn(ctx, chunk); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// ipAddressForms returns the canonical forms the IP address may be stored with.
+//
+// Returns the IP address as it is when it is not valid.
+func ipAddressForms(ip string) any {
+	forms := ipAddresses(ip)
+	if len(forms) == 1 {
+		return forms[0]
+	}
+
+	return forms
+}
+
+// ipAddresses returns the list of the canonical forms the IP address may be stored with, see ipAddressForms.
+func ipAddresses(ip string) []string {
+	canonical, err := model.CanonicalIPAddress(ip, model.UnmapIPv4)
+	if err != nil {
+		return []string{ip}
+	}
+
+	addr := netip.MustParseAddr(canonical)
+	if !addr.Is4() {
+		return []string{canonical}
+	}
+
+	return []string{canonical, netip.AddrFrom16(addr.As16()).String()}
+}
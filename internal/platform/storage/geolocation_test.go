@@ -2,8 +2,10 @@ package storage_test
 
 import (
 	"context"
+	"database/sql/driver"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/bool64/sqluct"
@@ -30,9 +32,14 @@ func TestGeolocation_SaveGeolocation_success(t *testing.T) {
 	geo, err := model.DecodeGeolocation(data[0])
 	require.NoError(t, err)
 
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM geolocation WHERE ip_address IN ($1) AND dataset = $2 AND deleted_at IS NOT NULL`).
+		WithArgs(geo.IPAddress, model.DefaultDataset).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(`
-		INSERT INTO geolocation (ip_address,country_code,country,city,latitude,longitude,mystery_value) 
-			VALUES ($1,$2,$3,$4,$5,$6,$7)
+		INSERT INTO geolocation (ip_address,country_code,country,city,latitude,longitude,mystery_value,region,postal_code,time_zone,asn,organization,accuracy_radius,attributes,version_id,dataset,source_file,source_line) 
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
+			ON CONFLICT DO NOTHING
 		`).
 		WithArgs(
 			geo.IPAddress,
@@ -42,8 +49,20 @@ func TestGeolocation_SaveGeolocation_success(t *testing.T) {
 			geo.Latitude,
 			geo.Longitude,
 			geo.MysteryValue,
+			geo.Region,
+			geo.PostalCode,
+			geo.TimeZone,
+			geo.ASN,
+			geo.Organization,
+			geo.AccuracyRadius,
+			geo.Attributes,
+			geo.VersionID,
+			model.DefaultDataset,
+			geo.SourceFile,
+			geo.SourceLine,
 		).
 		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
 
 	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
 
@@ -70,9 +89,14 @@ func TestGeolocation_SaveGeolocation_failure(t *testing.T) {
 	geo, err := model.DecodeGeolocation(data[0])
 	require.NoError(t, err)
 
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM geolocation WHERE ip_address IN ($1) AND dataset = $2 AND deleted_at IS NOT NULL`).
+		WithArgs(geo.IPAddress, model.DefaultDataset).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(`
-		INSERT INTO geolocation (ip_address,country_code,country,city,latitude,longitude,mystery_value) 
-			VALUES ($1,$2,$3,$4,$5,$6,$7)
+		INSERT INTO geolocation (ip_address,country_code,country,city,latitude,longitude,mystery_value,region,postal_code,time_zone,asn,organization,accuracy_radius,attributes,version_id,dataset,source_file,source_line) 
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
+			ON CONFLICT DO NOTHING
 		`).
 		WithArgs(
 			geo.IPAddress,
@@ -82,8 +106,20 @@ func TestGeolocation_SaveGeolocation_failure(t *testing.T) {
 			geo.Latitude,
 			geo.Longitude,
 			geo.MysteryValue,
+			geo.Region,
+			geo.PostalCode,
+			geo.TimeZone,
+			geo.ASN,
+			geo.Organization,
+			geo.AccuracyRadius,
+			geo.Attributes,
+			geo.VersionID,
+			model.DefaultDataset,
+			geo.SourceFile,
+			geo.SourceLine,
 		).
 		WillReturnError(errors.New("error"))
+	mock.ExpectRollback()
 
 	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
 
@@ -124,9 +160,14 @@ func TestGeolocation_SaveGeolocation_batch(t *testing.T) {
 
 	geos = append(geos, &geo3)
 
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM geolocation WHERE ip_address IN ($1,$2,$3) AND dataset = $4 AND deleted_at IS NOT NULL`).
+		WithArgs(geo1.IPAddress, geo2.IPAddress, geo3.IPAddress, model.DefaultDataset).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectExec(`
-		INSERT INTO geolocation (ip_address,country_code,country,city,latitude,longitude,mystery_value) 
-			VALUES ($1,$2,$3,$4,$5,$6,$7),($8,$9,$10,$11,$12,$13,$14),($15,$16,$17,$18,$19,$20,$21)
+		INSERT INTO geolocation (ip_address,country_code,country,city,latitude,longitude,mystery_value,region,postal_code,time_zone,asn,organization,accuracy_radius,attributes,version_id,dataset,source_file,source_line) 
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18),($19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36),($37,$38,$39,$40,$41,$42,$43,$44,$45,$46,$47,$48,$49,$50,$51,$52,$53,$54)
+			ON CONFLICT DO NOTHING
 		`).
 		WithArgs(
 			geo1.IPAddress,
@@ -136,6 +177,17 @@ func TestGeolocation_SaveGeolocation_batch(t *testing.T) {
 			geo1.Latitude,
 			geo1.Longitude,
 			geo1.MysteryValue,
+			geo1.Region,
+			geo1.PostalCode,
+			geo1.TimeZone,
+			geo1.ASN,
+			geo1.Organization,
+			geo1.AccuracyRadius,
+			geo1.Attributes,
+			geo1.VersionID,
+			model.DefaultDataset,
+			geo1.SourceFile,
+			geo1.SourceLine,
 			geo2.IPAddress,
 			geo2.CountryCode,
 			geo2.Country,
@@ -143,6 +195,17 @@ func TestGeolocation_SaveGeolocation_batch(t *testing.T) {
 			geo2.Latitude,
 			geo2.Longitude,
 			geo2.MysteryValue,
+			geo2.Region,
+			geo2.PostalCode,
+			geo2.TimeZone,
+			geo2.ASN,
+			geo2.Organization,
+			geo2.AccuracyRadius,
+			geo2.Attributes,
+			geo2.VersionID,
+			model.DefaultDataset,
+			geo2.SourceFile,
+			geo2.SourceLine,
 			geo3.IPAddress,
 			geo3.CountryCode,
 			geo3.Country,
@@ -150,8 +213,20 @@ func TestGeolocation_SaveGeolocation_batch(t *testing.T) {
 			geo3.Latitude,
 			geo3.Longitude,
 			geo3.MysteryValue,
+			geo3.Region,
+			geo3.PostalCode,
+			geo3.TimeZone,
+			geo3.ASN,
+			geo3.Organization,
+			geo3.AccuracyRadius,
+			geo3.Attributes,
+			geo3.VersionID,
+			model.DefaultDataset,
+			geo3.SourceFile,
+			geo3.SourceLine,
 		).
 		WillReturnResult(sqlmock.NewResult(3, 3))
+	mock.ExpectCommit()
 
 	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
 
@@ -163,6 +238,33 @@ func TestGeolocation_SaveGeolocation_batch(t *testing.T) {
 	require.NoError(t, mock.ExpectationsWereMet())
 }
 
+func TestGeolocation_MaxBatchSize(t *testing.T) {
+	t.Parallel()
+
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+	s := storage.NewGeolocation(st)
+
+	args := func(n int) int {
+		geos := make([]*model.Geolocation, n)
+		for i := range geos {
+			geos[i] = &model.Geolocation{}
+		}
+
+		_, args, err := st.InsertStmt(storage.GeolocationTable, geos).ToSql()
+		require.NoError(t, err)
+
+		return len(args)
+	}
+
+	// The largest batch is inserted in a single statement, within the Postgres limit of bind parameters.
+	require.LessOrEqual(t, args(s.MaxBatchSize()), 65535)
+	require.Greater(t, args(s.MaxBatchSize()+1), 65535)
+}
+
 func TestGeolocation_FindGeolocationByIP_success(t *testing.T) {
 	t.Parallel()
 
@@ -178,16 +280,34 @@ func TestGeolocation_FindGeolocationByIP_success(t *testing.T) {
 	geo, err := model.DecodeGeolocation(data[0])
 	require.NoError(t, err)
 
+	geo.Region = "Upravna enota Ljubljana"
+	geo.PostalCode = "1000"
+	geo.TimeZone = "Europe/Ljubljana"
+	geo.ASN = 2108
+	geo.Organization = "Telekom Slovenije"
+	geo.AccuracyRadius = 20
+	geo.Attributes = model.Attributes{"connection_type": "cable", "isp": "Telekom Slovenije"}
+	geo.VersionID = 7
+	geo.Dataset = model.DefaultDataset
+	geo.SourceFile = "data_dump.csv"
+	geo.SourceLine = 2
+
 	meQuery := mock.ExpectQuery(`
-				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value 
+				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code, time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line 
 				FROM geolocation
-				WHERE ip_address = $1
+				WHERE ip_address IN ($1,$2) AND dataset IN ($3) AND deleted_at IS NULL
 			`).
 		WithArgs(
 			geo.IPAddress,
+			"::ffff:"+geo.IPAddress,
+			model.DefaultDataset,
 		)
 
-	rows := sqlmock.NewRows([]string{"ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value"})
+	rows := sqlmock.NewRows([]string{
+		"ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value",
+		"region", "postal_code", "time_zone", "asn", "organization", "accuracy_radius", "attributes", "version_id", "dataset",
+		"source_file", "source_line",
+	})
 
 	rows.AddRow(
 		geo.IPAddress,
@@ -197,6 +317,17 @@ func TestGeolocation_FindGeolocationByIP_success(t *testing.T) {
 		geo.Latitude,
 		geo.Longitude,
 		geo.MysteryValue,
+		geo.Region,
+		geo.PostalCode,
+		geo.TimeZone,
+		geo.ASN,
+		geo.Organization,
+		geo.AccuracyRadius,
+		[]byte(`{"connection_type": "cable", "isp": "Telekom Slovenije"}`),
+		geo.VersionID,
+		model.DefaultDataset,
+		geo.SourceFile,
+		geo.SourceLine,
 	)
 
 	meQuery.WillReturnRows(rows)
@@ -205,7 +336,7 @@ func TestGeolocation_FindGeolocationByIP_success(t *testing.T) {
 
 	s := storage.NewGeolocation(st)
 
-	g, err := s.FindGeolocationByIP(context.Background(), geo.IPAddress)
+	g, err := s.FindGeolocationByIP(context.Background(), geo.IPAddress, nil)
 	require.NoError(t, err)
 
 	require.Equal(t, geo, g)
@@ -223,22 +354,474 @@ func TestGeolocation_FindGeolocationByIP_failure(t *testing.T) {
 	defer db.Close() //nolint:errcheck
 
 	_ = mock.ExpectQuery(`
-				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value 
+				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code, time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line 
 				FROM geolocation
-				WHERE ip_address = $1
+				WHERE ip_address IN ($1,$2) AND dataset IN ($3) AND deleted_at IS NULL
 			`).
 		WithArgs(
 			ip,
+			"::ffff:"+ip,
+			model.DefaultDataset,
 		).
-		WillReturnError(database.ErrNotFound)
+		WillReturnRows(sqlmock.NewRows([]string{"ip_address"}))
 
 	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
 
 	s := storage.NewGeolocation(st)
 
-	_, err = s.FindGeolocationByIP(context.Background(), ip)
-	require.Error(t, err)
-	require.ErrorContains(t, err, "not found")
+	_, err = s.FindGeolocationByIP(context.Background(), ip, nil)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_FindGeolocationByIP_canonical(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		ip   string
+		args []driver.Value
+	}{
+		{ip: "2001:DB8:0:0::1", args: []driver.Value{"2001:db8::1"}},
+		{ip: "::ffff:200.106.141.15", args: []driver.Value{"200.106.141.15", "::ffff:200.106.141.15"}},
+	} {
+		t.Run(tc.ip, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close() //nolint:errcheck
+
+			where := "ip_address = $1 AND dataset IN ($2)"
+			if len(tc.args) == 2 {
+				where = "ip_address IN ($1,$2) AND dataset IN ($3)"
+			}
+
+			mock.ExpectQuery(`
+				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code, time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line 
+				FROM geolocation
+				WHERE ` + where + ` AND deleted_at IS NULL
+			`).
+				WithArgs(append(tc.args, model.DefaultDataset)...).
+				WillReturnRows(
+					sqlmock.NewRows([]string{"ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value", "dataset"}).
+						AddRow(tc.args[0], "SI", "Nepal", "DuBuquemouth", -84.87503094689836, 7.206435933364332, 7823011346, model.DefaultDataset),
+				)
+
+			st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+			s := storage.NewGeolocation(st)
+
+			g, err := s.FindGeolocationByIP(context.Background(), tc.ip, nil)
+			require.NoError(t, err)
+			require.Equal(t, tc.args[0], g.IPAddress)
+
+			require.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestGeolocation_FindGeolocationsByIP_success(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadSampleData(2, 0)
+	require.NoError(t, err)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	geo1, err := model.DecodeGeolocation(data[0])
+	require.NoError(t, err)
+
+	geo2, err := model.DecodeGeolocation(data[1])
+	require.NoError(t, err)
+
+	rows := sqlmock.NewRows([]string{"ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value"})
+
+	rows.AddRow(
+		geo1.IPAddress,
+		geo1.CountryCode,
+		geo1.Country,
+		geo1.City,
+		geo1.Latitude,
+		geo1.Longitude,
+		geo1.MysteryValue,
+	)
+
+	mock.ExpectQuery(`
+				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code, time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line 
+				FROM geolocation
+				WHERE ip_address IN ($1,$2) AND dataset = $3 AND deleted_at IS NULL
+			`).
+		WithArgs(
+			geo1.IPAddress,
+			geo2.IPAddress,
+			model.DefaultDataset,
+		).
+		WillReturnRows(rows)
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	geos, err := s.FindGeolocationsByIP(context.Background(), []string{geo1.IPAddress, geo2.IPAddress})
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]model.Geolocation{geo1.IPAddress: geo1}, geos)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_UpdateGeolocation_success(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	// 200.106.141.15,SI,Nepal,DuBuquemouth,-84.87503094689836,7.206435933364332,7823011346
+	data, err := helpers.LoadSampleData(1, 0)
+	require.NoError(t, err)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	geo, err := model.DecodeGeolocation(data[0])
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`
+		UPDATE geolocation 
+		SET ip_address = $1, country_code = $2, country = $3, city = $4, latitude = $5, longitude = $6, mystery_value = $7, region = $8, postal_code = $9, time_zone = $10, asn = $11, organization = $12, accuracy_radius = $13, attributes = $14, version_id = $15, dataset = $16, source_file = $17, source_line = $18, updated_at = CURRENT_TIMESTAMP 
+		WHERE ip_address = $19 AND dataset = $20 AND deleted_at IS NULL
+		`).
+		WithArgs(
+			geo.IPAddress,
+			geo.CountryCode,
+			geo.Country,
+			geo.City,
+			geo.Latitude,
+			geo.Longitude,
+			geo.MysteryValue,
+			geo.Region,
+			geo.PostalCode,
+			geo.TimeZone,
+			geo.ASN,
+			geo.Organization,
+			geo.AccuracyRadius,
+			geo.Attributes,
+			geo.VersionID,
+			model.DefaultDataset,
+			geo.SourceFile,
+			geo.SourceLine,
+			geo.IPAddress,
+			model.DefaultDataset,
+		).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	err = s.UpdateGeolocation(context.Background(), []*model.Geolocation{&geo})
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_MergeGeolocation_rollback(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	// The insert is rolled back along with the failing update.
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM geolocation`).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`INSERT INTO geolocation`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE geolocation`).WillReturnError(driver.ErrBadConn)
+	mock.ExpectRollback()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	err = s.MergeGeolocation(context.Background(),
+		[]*model.Geolocation{{IPAddress: "160.103.7.140", City: "Prague"}},
+		[]*model.Geolocation{{IPAddress: "200.106.141.15", City: "Maribor"}},
+	)
+	require.ErrorIs(t, err, driver.ErrBadConn)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_ListGeolocation_success(t *testing.T) {
+	t.Parallel()
+
+	// Load sample data
+	data, err := helpers.LoadSampleData(2, 0)
+	require.NoError(t, err)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	rows := sqlmock.NewRows([]string{"ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value"})
+
+	var expected []model.Geolocation
+
+	for _, d := range data {
+		geo, err := model.DecodeGeolocation(d)
+		require.NoError(t, err)
+
+		rows.AddRow(
+			geo.IPAddress,
+			geo.CountryCode,
+			geo.Country,
+			geo.City,
+			geo.Latitude,
+			geo.Longitude,
+			geo.MysteryValue,
+		)
+
+		expected = append(expected, geo)
+	}
+
+	mock.ExpectQuery(`
+				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code, time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line 
+				FROM geolocation
+				WHERE dataset = $1 AND deleted_at IS NULL
+			`).
+		WithArgs(model.DefaultDataset).
+		WillReturnRows(rows)
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	var geos []model.Geolocation
+
+	err = s.ListGeolocation(context.Background(), func(geo model.Geolocation) error {
+		geos = append(geos, geo)
+
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, expected, geos)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_DeleteGeolocation_success(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM geolocation WHERE ip_address IN ($1,$2) AND dataset = $3`).
+		WithArgs("200.106.141.15", "160.103.7.140", model.DefaultDataset).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	err = s.DeleteGeolocation(context.Background(), []string{"200.106.141.15", "160.103.7.140"})
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_DeleteGeolocation_rollback(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM geolocation WHERE ip_address IN ($1,$2) AND dataset = $3`).
+		WithArgs("200.106.141.15", "160.103.7.140", model.DefaultDataset).
+		WillReturnError(driver.ErrBadConn)
+	mock.ExpectRollback()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	err = s.DeleteGeolocation(context.Background(), []string{"200.106.141.15", "160.103.7.140"})
+	require.ErrorIs(t, err, driver.ErrBadConn)
+
+	// Nothing to delete.
+	require.NoError(t, s.DeleteGeolocation(context.Background(), nil))
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_SoftDeleteGeolocation_success(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`
+		UPDATE geolocation 
+		SET deleted_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP 
+		WHERE ip_address IN ($1,$2) AND dataset = $3 AND deleted_at IS NULL
+		`).
+		WithArgs("200.106.141.15", "160.103.7.140", model.DefaultDataset).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	err = s.SoftDeleteGeolocation(context.Background(), []string{"200.106.141.15", "160.103.7.140"})
+	require.NoError(t, err)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_FindGeolocationByIPAsOf(t *testing.T) {
+	t.Parallel()
+
+	ip := "200.106.141.15"
+	asOf := time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	query := `
+				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code, time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line 
+				FROM geolocation_history
+				WHERE ip_address IN ($1,$2) AND dataset IN ($3) AND valid_from <= $4 AND (valid_to IS NULL OR valid_to > $5) 
+				ORDER BY valid_from DESC
+			`
+
+	rows := sqlmock.NewRows([]string{
+		"ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value", "version_id", "dataset",
+	}).AddRow(ip, "SI", "Slovenia", "Ljubljana", 46.0569, 14.5058, 7823011346, 7, model.DefaultDataset)
+
+	mock.ExpectQuery(query).
+		WithArgs(ip, "::ffff:"+ip, model.DefaultDataset, asOf, asOf).
+		WillReturnRows(rows)
+
+	mock.ExpectQuery(query).
+		WithArgs(ip, "::ffff:"+ip, model.DefaultDataset, asOf.AddDate(-2, 0, 0), asOf.AddDate(-2, 0, 0)).
+		WillReturnRows(sqlmock.NewRows([]string{"ip_address"}))
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st)
+
+	geo, err := s.FindGeolocationByIPAsOf(context.Background(), ip, asOf, nil)
+	require.NoError(t, err)
+	require.Equal(t, model.Geolocation{
+		IPAddress:    ip,
+		CountryCode:  "SI",
+		Country:      "Slovenia",
+		City:         "Ljubljana",
+		Latitude:     46.0569,
+		Longitude:    14.5058,
+		MysteryValue: 7823011346,
+		VersionID:    7,
+		Dataset:      model.DefaultDataset,
+	}, geo)
+
+	_, err = s.FindGeolocationByIPAsOf(context.Background(), ip, asOf.AddDate(-2, 0, 0), nil)
+	require.ErrorIs(t, err, database.ErrNotFound)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_FindGeolocationByIP_datasets(t *testing.T) {
+	t.Parallel()
+
+	ip := "200.106.141.15"
+	datasets := []string{"maxmind", "ip2location"}
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	query := `
+				SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code, time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line 
+				FROM geolocation
+				WHERE ip_address IN ($1,$2) AND dataset IN ($3,$4) AND deleted_at IS NULL
+			`
+
+	columns := []string{"ip_address", "country_code", "country", "city", "dataset"}
+
+	// Both datasets have the IP address, the first one answers.
+	mock.ExpectQuery(query).
+		WithArgs(ip, "::ffff:"+ip, "maxmind", "ip2location").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(ip, "SI", "Slovenia", "Maribor", "ip2location").
+			AddRow(ip, "SI", "Slovenia", "Ljubljana", "maxmind"),
+		)
+
+	// Only the second dataset has the IP address, it answers.
+	mock.ExpectQuery(query).
+		WithArgs(ip, "::ffff:"+ip, "maxmind", "ip2location").
+		WillReturnRows(sqlmock.NewRows(columns).
+			AddRow(ip, "SI", "Slovenia", "Maribor", "ip2location"),
+		)
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st, storage.WithDataset("maxmind"))
+
+	geo, err := s.FindGeolocationByIP(context.Background(), ip, datasets)
+	require.NoError(t, err)
+	require.Equal(t, "maxmind", geo.Dataset)
+	require.Equal(t, "Ljubljana", geo.City)
+
+	geo, err = s.FindGeolocationByIP(context.Background(), ip, datasets)
+	require.NoError(t, err)
+	require.Equal(t, "ip2location", geo.Dataset)
+	require.Equal(t, "Maribor", geo.City)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGeolocation_SaveGeolocation_dataset(t *testing.T) {
+	t.Parallel()
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close() //nolint:errcheck
+
+	geo := model.Geolocation{IPAddress: "200.106.141.15"}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(`DELETE FROM geolocation WHERE ip_address IN ($1) AND dataset = $2 AND deleted_at IS NOT NULL`).
+		WithArgs(geo.IPAddress, "maxmind").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`
+		INSERT INTO geolocation (ip_address,country_code,country,city,latitude,longitude,mystery_value,region,postal_code,time_zone,asn,organization,accuracy_radius,attributes,version_id,dataset,source_file,source_line) 
+			VALUES ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18)
+			ON CONFLICT DO NOTHING
+		`).
+		WithArgs(geo.IPAddress, "", "", "", 0.0, 0.0, int64(0), "", "", "", uint32(0), "", uint32(0), "{}", int64(0), "maxmind", "", uint64(0)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	st := sqluct.NewStorage(sqlx.NewDb(db, "sqlmock"))
+
+	s := storage.NewGeolocation(st, storage.WithDataset("maxmind"))
+
+	require.NoError(t, s.SaveGeolocation(context.Background(), []*model.Geolocation{&geo}))
+	require.Equal(t, "maxmind", geo.Dataset)
 
 	require.NoError(t, mock.ExpectationsWereMet())
 }
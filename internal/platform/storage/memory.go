@@ -0,0 +1,211 @@
+package storage
+
+import (
+	"cmp"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/platform/config"
+)
+
+// errNotMemoryDSN is the error opening a DSN not selecting the in-memory database.
+var errNotMemoryDSN = errors.New("not a memory DSN")
+
+// memories are the in-memory databases opened in the process, by name.
+//
+//nolint:gochecknoglobals
+var memories = struct {
+	sync.Mutex
+	dbs map[string]*Memory
+}{
+	dbs: make(map[string]*Memory),
+}
+
+// OpenMemory returns the in-memory database of the DSN, memory://name.
+//
+// In-memory databases are shared in the process by name, so that the service and the commands running in the same
+// process see the same geolocation data.
+func OpenMemory(dsn string) (*Memory, error) {
+	name, ok := strings.CutPrefix(dsn, config.BackendMemory+"://")
+	if !ok {
+		return nil, errNotMemoryDSN
+	}
+
+	memories.Lock()
+	defer memories.Unlock()
+
+	db, ok := memories.dbs[name]
+	if !ok {
+		db = NewMemory()
+		memories.dbs[name] = db
+	}
+
+	return db, nil
+}
+
+// memoryKey identifies the geolocation data of an IP address in a dataset.
+type memoryKey struct {
+	dataset string
+	ip      string
+}
+
+// memoryRow is a stored geolocation data.
+type memoryRow struct {
+	geo     model.Geolocation
+	deleted bool
+}
+
+// memoryVersion is a stored dataset version.
+type memoryVersion struct {
+	dataset   string
+	source    string
+	accepted  int
+	completed bool
+}
+
+// Memory is an in-memory database, for tests and small deployments not needing to persist the geolocation data.
+//
+// It keeps the geolocation data, its history and the dataset versions the same way the database does. The in-memory
+// repositories sharing a Memory see the same data.
+type Memory struct {
+	mu sync.RWMutex
+
+	rows    map[memoryKey][]*memoryRow
+	history []GeolocationHistory
+	// open is the position in history of the current state of the geolocation data, by key.
+	open        map[memoryKey]int
+	versions    map[int64]*memoryVersion
+	lastVersion int64
+}
+
+// NewMemory returns an empty in-memory database, not shared with OpenMemory.
+func NewMemory() *Memory {
+	m := Memory{}
+	m.reset()
+
+	return &m
+}
+
+// Reset removes all the data.
+func (m *Memory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reset()
+}
+
+func (m *Memory) reset() {
+	m.rows = make(map[memoryKey][]*memoryRow)
+	m.history = nil
+	m.open = make(map[memoryKey]int)
+	m.versions = make(map[int64]*memoryVersion)
+	m.lastVersion = 0
+}
+
+// Geolocations returns the stored geolocation data of all the datasets, soft deleted excluded, ordered by dataset and
+// IP address.
+func (m *Memory) Geolocations() []model.Geolocation {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var geos []model.Geolocation
+
+	for _, rows := range m.rows {
+		for _, row := range rows {
+			if !row.deleted {
+				geos = append(geos, cloneGeolocation(row.geo))
+			}
+		}
+	}
+
+	slices.SortStableFunc(geos, func(a, b model.Geolocation) int {
+		return cmp.Or(cmp.Compare(a.Dataset, b.Dataset), cmp.Compare(a.IPAddress, b.IPAddress))
+	})
+
+	return geos
+}
+
+// History returns the history of the geolocation data of all the datasets, in the order it was recorded.
+func (m *Memory) History() []GeolocationHistory {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	history := make([]GeolocationHistory, 0, len(m.history))
+
+	for _, h := range m.history {
+		h.Geolocation = cloneGeolocation(h.Geolocation)
+		history = append(history, h)
+	}
+
+	return history
+}
+
+// StoreHistory stores past states of the geolocation data as they are, i.e. to load the history of a database.
+func (m *Memory) StoreHistory(history ...GeolocationHistory) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, h := range history {
+		if h.Dataset == "" {
+			h.Dataset = model.DefaultDataset
+		}
+
+		h.Geolocation = cloneGeolocation(h.Geolocation)
+		m.history = append(m.history, h)
+
+		if h.ValidTo == nil {
+			m.open[memoryKey{dataset: h.Dataset, ip: h.IPAddress}] = len(m.history) - 1
+		}
+	}
+}
+
+// insert stores the geolocation data and records its history, unless the IP address is already stored in the dataset.
+// The soft deleted geolocation data of the IP address is purged. Must be called with the lock held.
+func (m *Memory) insert(geo model.Geolocation, now time.Time) {
+	key := memoryKey{dataset: geo.Dataset, ip: geo.IPAddress}
+
+	m.rows[key] = slices.DeleteFunc(m.rows[key], func(row *memoryRow) bool {
+		return row.deleted
+	})
+
+	if len(m.rows[key]) > 0 {
+		return
+	}
+
+	m.rows[key] = append(m.rows[key], &memoryRow{geo: geo})
+	m.record(geo, now)
+}
+
+// record starts a new state in the history of the geolocation data. Must be called with the lock held.
+func (m *Memory) record(geo model.Geolocation, now time.Time) {
+	m.open[memoryKey{dataset: geo.Dataset, ip: geo.IPAddress}] = len(m.history)
+	m.history = append(m.history, GeolocationHistory{
+		Geolocation: cloneGeolocation(geo),
+		ValidFrom:   now,
+	})
+}
+
+// closeHistory ends the current state in the history of the geolocation data of the key. Must be called with the lock
+// held.
+func (m *Memory) closeHistory(key memoryKey, now time.Time) {
+	i, ok := m.open[key]
+	if !ok {
+		return
+	}
+
+	m.history[i].ValidTo = &now
+
+	delete(m.open, key)
+}
+
+// cloneGeolocation returns a copy of the geolocation data not sharing its attributes.
+func cloneGeolocation(geo model.Geolocation) model.Geolocation {
+	geo.Attributes = maps.Clone(geo.Attributes)
+
+	return geo
+}
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+)
+
+// MemoryDatasetVersion represents a DatasetVersion repository backed by an in-memory database.
+type MemoryDatasetVersion struct {
+	db      *Memory
+	dataset string
+}
+
+// NewMemoryDatasetVersion returns instance of MemoryDatasetVersion repository, versioning the dataset set up with
+// WithDataset.
+func NewMemoryDatasetVersion(db *Memory, opts ...Option) *MemoryDatasetVersion {
+	return &MemoryDatasetVersion{
+		db:      db,
+		dataset: newOptions(opts).dataset,
+	}
+}
+
+// CreateDatasetVersion creates the dataset version of the import of the source.
+//
+// Returns the ID of the version.
+func (s *MemoryDatasetVersion) CreateDatasetVersion(_ context.Context, source string) (int64, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.db.lastVersion++
+	s.db.versions[s.db.lastVersion] = &memoryVersion{
+		dataset: s.dataset,
+		source:  source,
+	}
+
+	return s.db.lastVersion, nil
+}
+
+// CompleteDatasetVersion marks the dataset version as completed, with the number of geolocation data accepted.
+func (s *MemoryDatasetVersion) CompleteDatasetVersion(_ context.Context, id int64, accepted int) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	if v, ok := s.db.versions[id]; ok {
+		v.accepted = accepted
+		v.completed = true
+	}
+
+	return nil
+}
@@ -0,0 +1,264 @@
+package storage
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/bool64/ctxd"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/pkg/database"
+)
+
+// MemoryGeolocation represents a Geolocation repository backed by an in-memory database.
+//
+// The repository behaves as Geolocation, writes and lists the geolocation data of its dataset, see WithDataset.
+type MemoryGeolocation struct {
+	db      *Memory
+	dataset string
+}
+
+// NewMemoryGeolocation returns instance of MemoryGeolocation repository.
+func NewMemoryGeolocation(db *Memory, opts ...Option) *MemoryGeolocation {
+	return &MemoryGeolocation{
+		db:      db,
+		dataset: newOptions(opts).dataset,
+	}
+}
+
+// SaveGeolocation store the geolocation data in the dataset of the repository.
+func (s *MemoryGeolocation) SaveGeolocation(_ context.Context, geos []*model.Geolocation) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.save(geos, time.Now())
+
+	return nil
+}
+
+// save stores the geolocation data, the lock held.
+func (s *MemoryGeolocation) save(geos []*model.Geolocation, now time.Time) {
+	for _, geo := range geos {
+		geo.Dataset = s.dataset
+
+		s.db.insert(cloneGeolocation(*geo), now)
+	}
+}
+
+// FindGeolocationByIP get the geolocation data by IP from the first of the datasets having it, in order. The dataset
+// of the repository when no dataset is given.
+//
+// The IP address is looked up in its canonical forms, as Geolocation.FindGeolocationByIP does.
+func (s *MemoryGeolocation) FindGeolocationByIP(ctx context.Context, ip string, datasets []string) (model.Geolocation, error) {
+	errMsg := "storage.MemoryGeolocation: failed to get Geolocation by IP"
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	for _, dataset := range s.lookupDatasets(datasets) {
+		for _, form := range ipAddresses(ip) {
+			for _, row := range s.db.rows[memoryKey{dataset: dataset, ip: form}] {
+				if !row.deleted {
+					return cloneGeolocation(row.geo), nil
+				}
+			}
+		}
+	}
+
+	return model.Geolocation{}, ctxd.WrapError(ctx, database.ErrNotFound, errMsg)
+}
+
+// FindGeolocationByIPAsOf get the geolocation data by IP as it was at the given time.
+//
+// The IP address and the datasets are looked up as in FindGeolocationByIP.
+func (s *MemoryGeolocation) FindGeolocationByIPAsOf(
+	ctx context.Context,
+	ip string,
+	asOf time.Time,
+	datasets []string,
+) (model.Geolocation, error) {
+	errMsg := "storage.MemoryGeolocation: failed to get Geolocation by IP as of"
+
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	forms := ipAddresses(ip)
+
+	for _, dataset := range s.lookupDatasets(datasets) {
+		var found *GeolocationHistory
+
+		for i := range s.db.history {
+			h := &s.db.history[i]
+
+			if h.Dataset != dataset || !slices.Contains(forms, h.IPAddress) {
+				continue
+			}
+
+			if h.ValidFrom.After(asOf) || (h.ValidTo != nil && !h.ValidTo.After(asOf)) {
+				continue
+			}
+
+			if found == nil || h.ValidFrom.After(found.ValidFrom) {
+				found = h
+			}
+		}
+
+		if found != nil {
+			return cloneGeolocation(found.Geolocation), nil
+		}
+	}
+
+	return model.Geolocation{}, ctxd.WrapError(ctx, database.ErrNotFound, errMsg)
+}
+
+// lookupDatasets returns the datasets to look up, the dataset of the repository when none is given.
+func (s *MemoryGeolocation) lookupDatasets(datasets []string) []string {
+	if len(datasets) == 0 {
+		return []string{s.dataset}
+	}
+
+	return datasets
+}
+
+// FindGeolocationsByIP get the geolocation data of the given IPs in the dataset of the repository, indexed by IP.
+func (s *MemoryGeolocation) FindGeolocationsByIP(_ context.Context, ips []string) (map[string]model.Geolocation, error) {
+	s.db.mu.RLock()
+	defer s.db.mu.RUnlock()
+
+	res := make(map[string]model.Geolocation, len(ips))
+
+	for _, ip := range ips {
+		for _, row := range s.db.rows[memoryKey{dataset: s.dataset, ip: ip}] {
+			if !row.deleted {
+				res[ip] = cloneGeolocation(row.geo)
+			}
+		}
+	}
+
+	return res, nil
+}
+
+// UpdateGeolocation updates the geolocation data by IP in the dataset of the repository.
+func (s *MemoryGeolocation) UpdateGeolocation(_ context.Context, geos []*model.Geolocation) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	s.update(geos, time.Now())
+
+	return nil
+}
+
+// MergeGeolocation saves the new geolocation data and updates the changed one in the dataset of the repository, at
+// once.
+func (s *MemoryGeolocation) MergeGeolocation(_ context.Context, added, changed []*model.Geolocation) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	now := time.Now()
+
+	s.save(added, now)
+	s.update(changed, now)
+
+	return nil
+}
+
+// update updates the geolocation data by IP, the lock held.
+func (s *MemoryGeolocation) update(geos []*model.Geolocation, now time.Time) {
+	for _, geo := range geos {
+		geo.Dataset = s.dataset
+
+		key := memoryKey{dataset: s.dataset, ip: geo.IPAddress}
+
+		for _, row := range s.db.rows[key] {
+			if row.deleted {
+				continue
+			}
+
+			row.geo = cloneGeolocation(*geo)
+
+			s.db.closeHistory(key, now)
+			s.db.record(row.geo, now)
+		}
+	}
+}
+
+// ListGeolocation calls fn for each stored geolocation data of the dataset of the repository, ordered by IP address.
+func (s *MemoryGeolocation) ListGeolocation(_ context.Context, fn func(geo model.Geolocation) error) error {
+	var geos []model.Geolocation
+
+	s.db.mu.RLock()
+
+	for key, rows := range s.db.rows {
+		if key.dataset != s.dataset {
+			continue
+		}
+
+		for _, row := range rows {
+			if !row.deleted {
+				geos = append(geos, cloneGeolocation(row.geo))
+			}
+		}
+	}
+
+	s.db.mu.RUnlock()
+
+	slices.SortStableFunc(geos, func(a, b model.Geolocation) int {
+		return strings.Compare(a.IPAddress, b.IPAddress)
+	})
+
+	for _, geo := range geos {
+		if err := fn(geo); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeleteGeolocation deletes the geolocation data by IP from the dataset of the repository.
+func (s *MemoryGeolocation) DeleteGeolocation(_ context.Context, ips []string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	now := time.Now()
+
+	for _, ip := range ips {
+		key := memoryKey{dataset: s.dataset, ip: ip}
+
+		if _, ok := s.db.rows[key]; !ok {
+			continue
+		}
+
+		delete(s.db.rows, key)
+		s.db.closeHistory(key, now)
+	}
+
+	return nil
+}
+
+// SoftDeleteGeolocation marks the geolocation data of the dataset of the repository as deleted by IP.
+//
+// Soft deleted geolocation data is no longer found.
+func (s *MemoryGeolocation) SoftDeleteGeolocation(_ context.Context, ips []string) error {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+
+	now := time.Now()
+
+	for _, ip := range ips {
+		key := memoryKey{dataset: s.dataset, ip: ip}
+
+		for _, row := range s.db.rows[key] {
+			if row.deleted {
+				continue
+			}
+
+			row.deleted = true
+
+			s.db.closeHistory(key, now)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io/fs"
+	"os"
+
+	"github.com/bool64/ctxd"
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/resources/migrations"
+	"github.com/golang-migrate/migrate/v4"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+)
+
+// MigrationStatus is the status of a migration in the database.
+type MigrationStatus struct {
+	Version uint   `json:"version" yaml:"version"`
+	Name    string `json:"name" yaml:"name"`
+	Applied bool   `json:"applied" yaml:"applied"`
+	// Dirty tells the migration failed half way, and has to be fixed by hand.
+	Dirty bool `json:"dirty,omitempty" yaml:"dirty,omitempty"`
+}
+
+// Migrator applies the migrations embedded in the binary to the database, see resources/migrations.
+//
+// The migrations applied are kept in the schema_migrations table, as the migrate tool does. Postgres migrations are
+// applied under an advisory lock, so that concurrent migrators wait for each other instead of applying the same
+// migrations twice.
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+}
+
+// NewMigrator returns the migrator of the database of the dialect, Postgres or SQLite.
+//
+// The migrator owns db, closing it with Close.
+func NewMigrator(db *sql.DB, dialect sqluct.Dialect) (*Migrator, error) {
+	var (
+		fsys   fs.FS = migrations.Postgres
+		dir          = "."
+		driver database.Driver
+		err    error
+	)
+
+	switch dialect {
+	case sqluct.DialectSQLite3:
+		fsys, dir = migrations.SQLite, "sqlite"
+
+		driver, err = sqlite.WithInstance(db, &sqlite.Config{})
+	default:
+		driver, err = pgx.WithInstance(db, &pgx.Config{})
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := iofs.New(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, string(dialect), driver)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{m: m, source: src}, nil
+}
+
+// Up applies the migrations not yet applied.
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return ctxd.WrapError(ctx, err, "storage.Migrator: failed to apply migrations")
+	}
+
+	return nil
+}
+
+// Down rolls back the last steps migrations applied, all of them when steps is zero or more than applied.
+func (m *Migrator) Down(ctx context.Context, steps uint) error {
+	var (
+		err   error
+		short migrate.ErrShortLimit
+	)
+
+	if steps == 0 {
+		err = m.m.Down()
+	} else {
+		err = m.m.Steps(-int(steps))
+	}
+
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) && !errors.Is(err, migrate.ErrNilVersion) &&
+		!errors.As(err, &short) {
+		return ctxd.WrapError(ctx, err, "storage.Migrator: failed to roll back migrations", "steps", steps)
+	}
+
+	return nil
+}
+
+// Version returns the version of the last migration applied, zero when none is, and whether it is dirty.
+func (m *Migrator) Version(ctx context.Context) (uint, bool, error) {
+	version, dirty, err := m.m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		return 0, false, nil
+	}
+
+	if err != nil {
+		return 0, false, ctxd.WrapError(ctx, err, "storage.Migrator: failed to get version")
+	}
+
+	return version, dirty, nil
+}
+
+// Status returns the status of each migration, ordered by version.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	current, dirty, err := m.Version(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+
+	version, err := m.source.First()
+
+	for err == nil {
+		r, name, rerr := m.source.ReadUp(version)
+		if rerr != nil {
+			return nil, ctxd.WrapError(ctx, rerr, "storage.Migrator: failed to read migration", "version", version)
+		}
+
+		_ = r.Close() //nolint:errcheck
+
+		statuses = append(statuses, MigrationStatus{
+			Version: version,
+			Name:    name,
+			Applied: version <= current,
+			Dirty:   dirty && version == current,
+		})
+
+		version, err = m.source.Next(version)
+	}
+
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, ctxd.WrapError(ctx, err, "storage.Migrator: failed to list migrations")
+	}
+
+	return statuses, nil
+}
+
+// Close closes the database of the migrator.
+func (m *Migrator) Close() error {
+	srcErr, dbErr := m.m.Close()
+
+	return errors.Join(srcErr, dbErr)
+}
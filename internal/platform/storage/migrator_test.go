@@ -0,0 +1,68 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrator_sqlite(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "vio.db")
+
+	db, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+
+	m, err := storage.NewMigrator(db, sqluct.DialectSQLite3)
+	require.NoError(t, err)
+
+	defer m.Close() //nolint:errcheck
+
+	statuses, err := m.Status(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, statuses)
+	assert.Equal(t, storage.MigrationStatus{
+		Version: 20261019150000,
+		Name:    "create_geolocation_tables",
+	}, statuses[0])
+
+	require.NoError(t, m.Up(ctx))
+	// Applying again is a no-op.
+	require.NoError(t, m.Up(ctx))
+
+	version, dirty, err := m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, statuses[len(statuses)-1].Version, version)
+	assert.False(t, dirty)
+
+	statuses, err = m.Status(ctx)
+	require.NoError(t, err)
+
+	for _, s := range statuses {
+		assert.True(t, s.Applied, s.Name)
+	}
+
+	require.Greater(t, len(statuses), 1)
+	require.NoError(t, m.Down(ctx, 1))
+
+	version, _, err = m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, statuses[len(statuses)-2].Version, version)
+
+	require.NoError(t, m.Down(ctx, 0))
+
+	version, _, err = m.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, uint(0), version)
+
+	// Nothing left to roll back.
+	require.NoError(t, m.Down(ctx, 0))
+}
@@ -0,0 +1,46 @@
+package storage
+
+import (
+	"github.com/dohernandez/vio/internal/domain/model"
+)
+
+// colDataset is the column keeping the dataset of the row.
+const colDataset = "dataset"
+
+// Option sets up a repository.
+type Option func(o *options)
+
+type options struct {
+	dataset  string
+	replicas *Replicas
+}
+
+// WithDataset scopes the repository to the dataset, the geolocation data is written to and listed from it. Defaults
+// to model.DefaultDataset.
+func WithDataset(name string) Option {
+	return func(o *options) {
+		if name != "" {
+			o.dataset = name
+		}
+	}
+}
+
+// WithReplicas balances the lookups by IP of the repository across the read replicas, the geolocation data is still
+// written to and listed from the primary.
+func WithReplicas(r *Replicas) Option {
+	return func(o *options) {
+		o.replicas = r
+	}
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		dataset: model.DefaultDataset,
+	}
+
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/bool64/sqluct"
+)
+
+// defaultReplicaCooldown is the time a failing replica is left out of the balancing.
+const defaultReplicaCooldown = 30 * time.Second
+
+// Replicas balances the lookups across the read replicas of the database, round robin, falling back to the primary.
+//
+// A replica failing a lookup is deemed unhealthy and left out for the cooldown, the lookup is retried on the primary.
+// Lookups go to the primary when none of the replicas is healthy.
+type Replicas struct {
+	replicas []*replica
+	next     atomic.Uint64
+	cooldown time.Duration
+	now      func() time.Time
+}
+
+// replica is a read replica, along with the time until it is unhealthy, in Unix nanoseconds.
+type replica struct {
+	storage        *sqluct.Storage
+	unhealthyUntil atomic.Int64
+}
+
+// ReplicasOption sets up Replicas.
+type ReplicasOption func(r *Replicas)
+
+// WithReplicaCooldown sets the time a failing replica is left out of the balancing, 30s by default.
+func WithReplicaCooldown(d time.Duration) ReplicasOption {
+	return func(r *Replicas) {
+		r.cooldown = d
+	}
+}
+
+// WithReplicaClock sets the clock telling whether the cooldown of a replica is over.
+func WithReplicaClock(now func() time.Time) ReplicasOption {
+	return func(r *Replicas) {
+		r.now = now
+	}
+}
+
+// NewReplicas returns the balancer of the read replicas, all of them healthy.
+func NewReplicas(replicas []*sqluct.Storage, opts ...ReplicasOption) *Replicas {
+	r := Replicas{
+		replicas: make([]*replica, 0, len(replicas)),
+		cooldown: defaultReplicaCooldown,
+		now:      time.Now,
+	}
+
+	for _, st := range replicas {
+		r.replicas = append(r.replicas, &replica{storage: st})
+	}
+
+	for _, opt := range opts {
+		opt(&r)
+	}
+
+	return &r
+}
+
+// Read runs the lookup on the next healthy replica, or on the primary when none is healthy or the replica fails.
+//
+// Lookups run on the primary with nil Replicas.
+func (r *Replicas) Read(ctx context.Context, primary *sqluct.Storage, lookup func(st *sqluct.Storage) error) error {
+	rep := r.pick()
+	if rep == nil {
+		return lookup(primary)
+	}
+
+	err := lookup(rep.storage)
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	rep.unhealthyUntil.Store(r.now().Add(r.cooldown).UnixNano())
+
+	return lookup(primary)
+}
+
+// pick returns the next healthy replica, nil when none is.
+func (r *Replicas) pick() *replica {
+	if r == nil || len(r.replicas) == 0 {
+		return nil
+	}
+
+	now := r.now().UnixNano()
+	start := r.next.Add(1) - 1
+
+	for i := range uint64(len(r.replicas)) {
+		rep := r.replicas[(start+i)%uint64(len(r.replicas))]
+
+		if rep.unhealthyUntil.Load() <= now {
+			return rep
+		}
+	}
+
+	return nil
+}
+
+// Healthy returns the number of healthy replicas.
+func (r *Replicas) Healthy() int {
+	if r == nil {
+		return 0
+	}
+
+	now := r.now().UnixNano()
+	healthy := 0
+
+	for _, rep := range r.replicas {
+		if rep.unhealthyUntil.Load() <= now {
+			healthy++
+		}
+	}
+
+	return healthy
+}
@@ -0,0 +1,77 @@
+package storage_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicas(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	primary := newSQLiteStorage(t)
+	replica1 := newSQLiteStorage(t)
+	replica2 := newSQLiteStorage(t)
+
+	// Each database has its own city, to tell where the lookup ran.
+	for st, city := range map[*sqluct.Storage]string{primary: "Ljubljana", replica1: "Maribor", replica2: "Koper"} {
+		require.NoError(t, storage.NewGeolocation(st).SaveGeolocation(ctx, []*model.Geolocation{
+			{IPAddress: "200.106.141.15", City: city},
+		}))
+	}
+
+	now := time.Now()
+	replicas := storage.NewReplicas([]*sqluct.Storage{replica1, replica2},
+		storage.WithReplicaCooldown(time.Minute),
+		storage.WithReplicaClock(func() time.Time { return now }),
+	)
+
+	s := storage.NewGeolocation(primary, storage.WithReplicas(replicas))
+
+	lookup := func() string {
+		t.Helper()
+
+		geo, err := s.FindGeolocationByIP(ctx, "200.106.141.15", nil)
+		require.NoError(t, err)
+
+		return geo.City
+	}
+
+	// Lookups are balanced across the replicas.
+	assert.Equal(t, []string{"Maribor", "Koper", "Maribor", "Koper"}, []string{lookup(), lookup(), lookup(), lookup()})
+
+	// Geolocation data is written to the primary.
+	require.NoError(t, s.SaveGeolocation(ctx, []*model.Geolocation{{IPAddress: "160.103.7.140", City: "Prague"}}))
+
+	geos, err := s.FindGeolocationsByIP(ctx, []string{"160.103.7.140"})
+	require.NoError(t, err)
+	assert.Len(t, geos, 1)
+
+	// A failing replica falls back to the primary, and is left out for the cooldown.
+	require.NoError(t, replica1.DB().Close())
+
+	assert.Equal(t, "Ljubljana", lookup())
+	assert.Equal(t, 1, replicas.Healthy())
+	assert.Equal(t, []string{"Koper", "Koper"}, []string{lookup(), lookup()})
+
+	now = now.Add(time.Minute)
+
+	assert.Equal(t, 2, replicas.Healthy())
+
+	// None of the replicas is healthy, the lookups go to the primary.
+	require.NoError(t, replica2.DB().Close())
+
+	assert.Equal(t, []string{"Ljubljana", "Ljubljana", "Ljubljana"}, []string{lookup(), lookup(), lookup()})
+	assert.Equal(t, 0, replicas.Healthy())
+
+	_, err = s.FindGeolocationByIPAsOf(ctx, "200.106.141.15", time.Now().Add(time.Minute), nil)
+	require.NoError(t, err)
+}
@@ -0,0 +1,20 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/bool64/sqluct"
+)
+
+// sqliteTimeFormat is the format SQLite keeps the timestamps with, in UTC, see the SQLite migrations. Timestamps in
+// this format compare as text.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000"
+
+// timeArg returns the argument comparing the time with the timestamps of the storage.
+func timeArg(storage *sqluct.Storage, t time.Time) any {
+	if storage.Mapper != nil && storage.Mapper.Dialect == sqluct.DialectSQLite3 {
+		return t.UTC().Format(sqliteTimeFormat)
+	}
+
+	return t
+}
@@ -0,0 +1,135 @@
+package storage_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/bool64/sqluct"
+	"github.com/dohernandez/vio/internal/domain/model"
+	"github.com/dohernandez/vio/internal/platform/storage"
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite" // SQLite driver
+)
+
+// sqliteTimeFormat is the format the timestamps are kept with by the SQLite migrations.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000"
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+
+	testBackend(t, newSQLiteBackend)
+}
+
+func TestSQLite_SaveGeolocation_maxBatchSize(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := storage.NewGeolocation(newSQLiteStorage(t))
+
+	save := func(n int) error {
+		geos := make([]*model.Geolocation, n)
+		for i := range geos {
+			geos[i] = &model.Geolocation{IPAddress: fmt.Sprintf("10.0.%d.%d", i/256, i%256), CountryCode: "SI"}
+		}
+
+		return s.SaveGeolocation(ctx, geos)
+	}
+
+	// The largest batch is inserted in a single statement, within the SQLite limit of bind parameters.
+	require.NoError(t, save(s.MaxBatchSize()))
+	require.ErrorContains(t, save(s.MaxBatchSize()+1), "too many SQL variables")
+}
+
+// newSQLiteBackend returns a backend on a SQLite database, see newSQLiteStorage.
+func newSQLiteBackend(t *testing.T) backend {
+	t.Helper()
+
+	ctx := context.Background()
+	st := newSQLiteStorage(t)
+
+	return backend{geolocation: func(opts ...storage.Option) geolocationRepository {
+		return storage.NewGeolocation(st, opts...)
+	},
+		datasetVersion: func(opts ...storage.Option) datasetVersionRepository {
+			return storage.NewDatasetVersion(st, opts...)
+		},
+		geolocations: func(t *testing.T) []model.Geolocation {
+			t.Helper()
+
+			var geos []model.Geolocation
+
+			q := st.SelectStmt(storage.GeolocationTable, model.Geolocation{}).
+				Where(squirrel.Eq{"deleted_at": nil}).
+				OrderBy("dataset", "ip_address")
+
+			require.NoError(t, st.Select(ctx, q, &geos))
+
+			return geos
+		},
+		history: func(t *testing.T) []storage.GeolocationHistory {
+			t.Helper()
+
+			var history []storage.GeolocationHistory
+
+			q := st.SelectStmt(storage.GeolocationHistoryTable, storage.GeolocationHistory{}).OrderBy("id")
+
+			require.NoError(t, st.Select(ctx, q, &history))
+
+			return history
+		},
+		storeHistory: func(t *testing.T, history ...storage.GeolocationHistory) {
+			t.Helper()
+
+			for _, h := range history {
+				var validTo any
+
+				if h.ValidTo != nil {
+					validTo = h.ValidTo.UTC().Format(sqliteTimeFormat)
+				}
+
+				q := st.QueryBuilder().Insert(storage.GeolocationHistoryTable).
+					Columns("ip_address", "country_code", "country", "city", "latitude", "longitude", "mystery_value",
+						"dataset", "valid_from", "valid_to").
+					Values(h.IPAddress, h.CountryCode, h.Country, h.City, h.Latitude, h.Longitude, h.MysteryValue,
+						h.Dataset, h.ValidFrom.UTC().Format(sqliteTimeFormat), validTo)
+
+				_, err := st.Exec(ctx, q)
+				require.NoError(t, err)
+			}
+		},
+	}
+}
+
+// newSQLiteStorage returns the storage of a SQLite database, in a temporary file, migrated with the embedded migrations.
+func newSQLiteStorage(t *testing.T) *sqluct.Storage {
+	t.Helper()
+
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "vio.db")
+
+	mdb, err := sql.Open("sqlite", path)
+	require.NoError(t, err)
+
+	m, err := storage.NewMigrator(mdb, sqluct.DialectSQLite3)
+	require.NoError(t, err)
+	require.NoError(t, m.Up(ctx))
+	require.NoError(t, m.Close())
+
+	db, err := sqlx.Open("sqlite", path)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	st := sqluct.NewStorage(db)
+	st.Format = squirrel.Question
+	st.Mapper = &sqluct.Mapper{Dialect: sqluct.DialectSQLite3}
+
+	return st
+}
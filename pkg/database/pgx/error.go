@@ -2,35 +2,81 @@ package pgx
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"errors"
+	"io"
+	"net"
 
 	"github.com/jackc/pgconn"
 	"github.com/jackc/pgerrcode"
+	pgconnv5 "github.com/jackc/pgx/v5/pgconn"
 )
 
 // IsUniqueViolation checks whether the error is unique violation.
 func IsUniqueViolation(err error) bool {
-	var pgErr *pgconn.PgError
-
-	if !errors.As(err, &pgErr) {
-		return false
-	}
+	code, ok := errorCode(err)
 
-	return pgErr.Code == pgerrcode.UniqueViolation
+	return ok && code == pgerrcode.UniqueViolation
 }
 
 // IsForeignKeyViolation checks whether the error is foreign key violation.
 func IsForeignKeyViolation(err error) bool {
-	var pgErr *pgconn.PgError
-
-	if !errors.As(err, &pgErr) {
-		return false
-	}
+	code, ok := errorCode(err)
 
-	return pgErr.Code == pgerrcode.ForeignKeyViolation
+	return ok && code == pgerrcode.ForeignKeyViolation
 }
 
 // IsNoRows checks whether the error is sql.ErrNoRows.
 func IsNoRows(err error) bool {
 	return errors.Is(err, sql.ErrNoRows)
 }
+
+// IsRetryable checks whether the error is transient, the statement may succeed when retried: serialization failures,
+// deadlocks, connection loss and the server shutting down or out of connections.
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if code, ok := errorCode(err); ok {
+		return pgerrcode.IsConnectionException(code) ||
+			code == pgerrcode.SerializationFailure ||
+			code == pgerrcode.DeadlockDetected ||
+			code == pgerrcode.TooManyConnections ||
+			code == pgerrcode.AdminShutdown ||
+			code == pgerrcode.CrashShutdown ||
+			code == pgerrcode.CannotConnectNow
+	}
+
+	var opErr *net.OpError
+
+	return pgconnv5.SafeToRetry(err) ||
+		errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.As(err, &opErr)
+}
+
+// IsDataError checks whether the error is caused by the data of the statement: a data exception, i.e. an invalid
+// text representation, or an integrity constraint violation.
+func IsDataError(err error) bool {
+	code, ok := errorCode(err)
+
+	return ok && (pgerrcode.IsDataException(code) || pgerrcode.IsIntegrityConstraintViolation(code))
+}
+
+// errorCode returns the SQLSTATE code of the Postgres error, of either pgx/v5 or pgconn.
+func errorCode(err error) (string, bool) {
+	var pgErrV5 *pgconnv5.PgError
+
+	if errors.As(err, &pgErrV5) {
+		return pgErrV5.Code, true
+	}
+
+	var pgErr *pgconn.PgError
+
+	if errors.As(err, &pgErr) {
+		return pgErr.Code, true
+	}
+
+	return "", false
+}
@@ -0,0 +1,57 @@
+package pgx_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/dohernandez/vio/pkg/database/pgx"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRetryable(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		err       error
+		retryable bool
+	}{
+		{err: nil},
+		{err: sql.ErrNoRows},
+		{err: errors.New("boom")},
+		{err: &pgconn.PgError{Code: pgerrcode.UniqueViolation}},
+		{err: &pgconn.PgError{Code: pgerrcode.InvalidTextRepresentation}},
+		{err: &pgconn.PgError{Code: pgerrcode.SerializationFailure}, retryable: true},
+		{err: &pgconn.PgError{Code: pgerrcode.DeadlockDetected}, retryable: true},
+		{err: &pgconn.PgError{Code: pgerrcode.ConnectionFailure}, retryable: true},
+		{err: &pgconn.PgError{Code: pgerrcode.AdminShutdown}, retryable: true},
+		{err: fmt.Errorf("exec: %w", &pgconn.PgError{Code: pgerrcode.SerializationFailure}), retryable: true},
+		{err: driver.ErrBadConn, retryable: true},
+		{err: &net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, retryable: true},
+	} {
+		assert.Equal(t, tc.retryable, pgx.IsRetryable(tc.err), tc.err)
+	}
+}
+
+func TestIsDataError(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, pgx.IsDataError(&pgconn.PgError{Code: pgerrcode.InvalidTextRepresentation}))
+	assert.True(t, pgx.IsDataError(fmt.Errorf("exec: %w", &pgconn.PgError{Code: pgerrcode.NotNullViolation})))
+	assert.False(t, pgx.IsDataError(&pgconn.PgError{Code: pgerrcode.SerializationFailure}))
+	assert.False(t, pgx.IsDataError(&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}))
+	assert.False(t, pgx.IsDataError(errors.New("boom")))
+}
+
+func TestIsUniqueViolation(t *testing.T) {
+	t.Parallel()
+
+	assert.True(t, pgx.IsUniqueViolation(fmt.Errorf("exec: %w", &pgconn.PgError{Code: pgerrcode.UniqueViolation})))
+	assert.False(t, pgx.IsUniqueViolation(&pgconn.PgError{Code: pgerrcode.SerializationFailure}))
+	assert.False(t, pgx.IsUniqueViolation(errors.New("boom")))
+}
@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	VioService_GeolocationByIPExposer_FullMethodName = "/api.vio.VioService/GeolocationByIPExposer"
+	VioService_GeolocationDetail_FullMethodName      = "/api.vio.VioService/GeolocationDetail"
 )
 
 // VioServiceClient is the client API for VioService service.
@@ -32,6 +33,10 @@ type VioServiceClient interface {
 	//
 	// Receives a request with the ip. Responses with the geolocation data otherwise not.
 	GeolocationByIPExposer(ctx context.Context, in *GeolocationByIPExposerRequest, opts ...grpc.CallOption) (*GeolocationByIPExposerResponse, error)
+	// GeolocationDetail exposes the geolocation data by IP along with its provenance, to debug wrong locations.
+	//
+	// Admin only, the request must be authorized with the admin token.
+	GeolocationDetail(ctx context.Context, in *GeolocationDetailRequest, opts ...grpc.CallOption) (*GeolocationDetailResponse, error)
 }
 
 type vioServiceClient struct {
@@ -52,6 +57,16 @@ func (c *vioServiceClient) GeolocationByIPExposer(ctx context.Context, in *Geolo
 	return out, nil
 }
 
+func (c *vioServiceClient) GeolocationDetail(ctx context.Context, in *GeolocationDetailRequest, opts ...grpc.CallOption) (*GeolocationDetailResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GeolocationDetailResponse)
+	err := c.cc.Invoke(ctx, VioService_GeolocationDetail_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // VioServiceServer is the server API for VioService service.
 // All implementations must embed UnimplementedVioServiceServer
 // for forward compatibility.
@@ -62,6 +77,10 @@ type VioServiceServer interface {
 	//
 	// Receives a request with the ip. Responses with the geolocation data otherwise not.
 	GeolocationByIPExposer(context.Context, *GeolocationByIPExposerRequest) (*GeolocationByIPExposerResponse, error)
+	// GeolocationDetail exposes the geolocation data by IP along with its provenance, to debug wrong locations.
+	//
+	// Admin only, the request must be authorized with the admin token.
+	GeolocationDetail(context.Context, *GeolocationDetailRequest) (*GeolocationDetailResponse, error)
 	mustEmbedUnimplementedVioServiceServer()
 }
 
@@ -75,6 +94,9 @@ type UnimplementedVioServiceServer struct{}
 func (UnimplementedVioServiceServer) GeolocationByIPExposer(context.Context, *GeolocationByIPExposerRequest) (*GeolocationByIPExposerResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GeolocationByIPExposer not implemented")
 }
+func (UnimplementedVioServiceServer) GeolocationDetail(context.Context, *GeolocationDetailRequest) (*GeolocationDetailResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GeolocationDetail not implemented")
+}
 func (UnimplementedVioServiceServer) mustEmbedUnimplementedVioServiceServer() {}
 func (UnimplementedVioServiceServer) testEmbeddedByValue()                    {}
 
@@ -114,6 +136,24 @@ func _VioService_GeolocationByIPExposer_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _VioService_GeolocationDetail_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GeolocationDetailRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(VioServiceServer).GeolocationDetail(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: VioService_GeolocationDetail_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(VioServiceServer).GeolocationDetail(ctx, req.(*GeolocationDetailRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // VioService_ServiceDesc is the grpc.ServiceDesc for VioService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -125,6 +165,10 @@ var VioService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GeolocationByIPExposer",
 			Handler:    _VioService_GeolocationByIPExposer_Handler,
 		},
+		{
+			MethodName: "GeolocationDetail",
+			Handler:    _VioService_GeolocationDetail_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "service.proto",
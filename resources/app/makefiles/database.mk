@@ -6,18 +6,18 @@ create-migration: migrate-cli
 	@git add ./resources/migrations
 
 ## Apply migrations
-migrate: migrate-cli
+migrate:
 	@echo ">> running migrations"
-	@migrate -source=file://./resources/migrations/ -database="$(DATABASE_DSN)" up
+	@$(GO) run ./cmd/vio migrate up
 
-## Rollback migrations
-migrate-down: migrate-cli
+## Rollback the last migration
+migrate-down:
 	@echo ">> rolling back migrations"
-	@migrate -source=file://./resources/migrations/ -database="$(DATABASE_DSN)" down
+	@$(GO) run ./cmd/vio migrate down
 
 
 ## Check/install migrations tool
 migrate-cli:
 	@bash $(APP_SCRIPTS)/migrate-cli.sh
 
-.PHONY: create-migration migrate migrate-cli
+.PHONY: create-migration migrate migrate-down migrate-cli
@@ -0,0 +1 @@
+ALTER TABLE "geolocation" DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,7 @@
+ALTER TABLE "geolocation"
+    DROP COLUMN IF EXISTS region,
+    DROP COLUMN IF EXISTS postal_code,
+    DROP COLUMN IF EXISTS time_zone,
+    DROP COLUMN IF EXISTS asn,
+    DROP COLUMN IF EXISTS organization,
+    DROP COLUMN IF EXISTS accuracy_radius;
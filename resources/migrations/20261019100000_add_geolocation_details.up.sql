@@ -0,0 +1,7 @@
+ALTER TABLE "geolocation"
+    ADD COLUMN IF NOT EXISTS region          VARCHAR(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS postal_code     VARCHAR(20) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS time_zone       VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS asn             BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS organization    VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS accuracy_radius INTEGER NOT NULL DEFAULT 0;
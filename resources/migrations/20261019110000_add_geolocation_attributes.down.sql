@@ -0,0 +1,2 @@
+ALTER TABLE "geolocation"
+    DROP COLUMN IF EXISTS attributes;
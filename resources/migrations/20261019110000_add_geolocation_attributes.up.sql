@@ -0,0 +1,2 @@
+ALTER TABLE "geolocation"
+    ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS geolocation_history_record ON geolocation;
+DROP FUNCTION IF EXISTS geolocation_history_record();
+DROP TABLE IF EXISTS "geolocation_history";
+
+ALTER TABLE "geolocation" DROP COLUMN IF EXISTS version_id;
+
+DROP TABLE IF EXISTS "dataset_version";
@@ -0,0 +1,78 @@
+CREATE TABLE IF NOT EXISTS "dataset_version" (
+    id BIGSERIAL PRIMARY KEY,
+
+    source       TEXT NOT NULL,
+    accepted     INTEGER NOT NULL DEFAULT 0,
+
+    created_at   TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- completed_at is NULL while the import is running, or when it failed.
+    completed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- version_id is the dataset version which last inserted or changed the geolocation, 0 when unknown.
+ALTER TABLE "geolocation" ADD COLUMN IF NOT EXISTS version_id BIGINT NOT NULL DEFAULT 0;
+
+-- geolocation_history keeps every state of the geolocation data, valid from valid_from until valid_to, excluded.
+-- The current state has no valid_to.
+CREATE TABLE IF NOT EXISTS "geolocation_history" (
+    id BIGSERIAL PRIMARY KEY,
+
+    ip_address      INET NOT NULL,
+    country_code    CHAR(2) NOT NULL,
+    country         VARCHAR(100) NOT NULL,
+    city            VARCHAR(100) NOT NULL,
+    latitude        NUMERIC(17, 15) NOT NULL,
+    longitude       NUMERIC(18, 15) NOT NULL,
+    mystery_value   BIGINT,
+    region          VARCHAR(100) NOT NULL DEFAULT '',
+    postal_code     VARCHAR(20) NOT NULL DEFAULT '',
+    time_zone       VARCHAR(64) NOT NULL DEFAULT '',
+    asn             BIGINT NOT NULL DEFAULT 0,
+    organization    VARCHAR(255) NOT NULL DEFAULT '',
+    accuracy_radius INTEGER NOT NULL DEFAULT 0,
+    attributes      JSONB NOT NULL DEFAULT '{}',
+    version_id      BIGINT NOT NULL DEFAULT 0,
+
+    valid_from TIMESTAMP WITH TIME ZONE NOT NULL,
+    valid_to   TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS geolocation_history_ip_address_idx ON geolocation_history(ip_address, valid_from);
+
+-- The history is kept by the database, so that every write to the geolocation table is recorded.
+CREATE OR REPLACE FUNCTION geolocation_history_record() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE geolocation_history
+        SET valid_to = CURRENT_TIMESTAMP
+        WHERE ip_address = OLD.ip_address AND valid_to IS NULL;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        INSERT INTO geolocation_history (
+            ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code,
+            time_zone, asn, organization, accuracy_radius, attributes, version_id, valid_from
+        ) VALUES (
+            NEW.ip_address, NEW.country_code, NEW.country, NEW.city, NEW.latitude, NEW.longitude, NEW.mystery_value,
+            NEW.region, NEW.postal_code, NEW.time_zone, NEW.asn, NEW.organization, NEW.accuracy_radius,
+            NEW.attributes, NEW.version_id, CURRENT_TIMESTAMP
+        );
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER geolocation_history_record
+    AFTER INSERT OR UPDATE OR DELETE ON geolocation
+    FOR EACH ROW EXECUTE FUNCTION geolocation_history_record();
+
+-- The geolocation data stored so far is valid since it was last updated.
+INSERT INTO geolocation_history (
+    ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code,
+    time_zone, asn, organization, accuracy_radius, attributes, version_id, valid_from
+)
+SELECT ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code,
+    time_zone, asn, organization, accuracy_radius, attributes, version_id, COALESCE(updated_at, CURRENT_TIMESTAMP)
+FROM geolocation
+WHERE deleted_at IS NULL;
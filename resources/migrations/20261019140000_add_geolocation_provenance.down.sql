@@ -0,0 +1,30 @@
+CREATE OR REPLACE FUNCTION geolocation_history_record() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE geolocation_history
+        SET valid_to = CURRENT_TIMESTAMP
+        WHERE ip_address = OLD.ip_address AND dataset = OLD.dataset AND valid_to IS NULL;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        INSERT INTO geolocation_history (
+            ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code,
+            time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, valid_from
+        ) VALUES (
+            NEW.ip_address, NEW.country_code, NEW.country, NEW.city, NEW.latitude, NEW.longitude, NEW.mystery_value,
+            NEW.region, NEW.postal_code, NEW.time_zone, NEW.asn, NEW.organization, NEW.accuracy_radius,
+            NEW.attributes, NEW.version_id, NEW.dataset, CURRENT_TIMESTAMP
+        );
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE "geolocation_history"
+    DROP COLUMN IF EXISTS source_file,
+    DROP COLUMN IF EXISTS source_line;
+
+ALTER TABLE "geolocation"
+    DROP COLUMN IF EXISTS source_file,
+    DROP COLUMN IF EXISTS source_line;
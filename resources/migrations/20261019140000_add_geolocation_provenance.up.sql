@@ -0,0 +1,32 @@
+-- The provenance of the geolocation data, the run importing it is the dataset version.
+ALTER TABLE "geolocation"
+    ADD COLUMN IF NOT EXISTS source_file VARCHAR(1024) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS source_line BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE "geolocation_history"
+    ADD COLUMN IF NOT EXISTS source_file VARCHAR(1024) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS source_line BIGINT NOT NULL DEFAULT 0;
+
+CREATE OR REPLACE FUNCTION geolocation_history_record() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE geolocation_history
+        SET valid_to = CURRENT_TIMESTAMP
+        WHERE ip_address = OLD.ip_address AND dataset = OLD.dataset AND valid_to IS NULL;
+    END IF;
+
+    IF TG_OP IN ('INSERT', 'UPDATE') AND NEW.deleted_at IS NULL THEN
+        INSERT INTO geolocation_history (
+            ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code,
+            time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line,
+            valid_from
+        ) VALUES (
+            NEW.ip_address, NEW.country_code, NEW.country, NEW.city, NEW.latitude, NEW.longitude, NEW.mystery_value,
+            NEW.region, NEW.postal_code, NEW.time_zone, NEW.asn, NEW.organization, NEW.accuracy_radius,
+            NEW.attributes, NEW.version_id, NEW.dataset, NEW.source_file, NEW.source_line, CURRENT_TIMESTAMP
+        );
+    END IF;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,20 @@
+DROP TABLE IF EXISTS "geolocation_bulk_index";
+
+ALTER TABLE "geolocation" RENAME TO "geolocation_partitioned";
+ALTER SEQUENCE geolocation_id_seq OWNED BY NONE;
+
+CREATE TABLE "geolocation" (LIKE "geolocation_partitioned" INCLUDING DEFAULTS);
+
+ALTER TABLE "geolocation" ADD PRIMARY KEY (id);
+ALTER SEQUENCE geolocation_id_seq OWNED BY geolocation.id;
+
+INSERT INTO "geolocation" SELECT * FROM "geolocation_partitioned";
+
+DROP TABLE "geolocation_partitioned";
+
+CREATE INDEX IF NOT EXISTS geolocation_ip_address_idx ON geolocation(ip_address);
+CREATE INDEX IF NOT EXISTS geolocation_dataset_ip_address_idx ON geolocation(dataset, ip_address);
+
+CREATE TRIGGER geolocation_history_record
+    AFTER INSERT OR UPDATE OR DELETE ON geolocation
+    FOR EACH ROW EXECUTE FUNCTION geolocation_history_record();
@@ -0,0 +1,34 @@
+-- geolocation is partitioned by IP family, the IPv4 and IPv6 geolocation data is kept, indexed and loaded apart.
+-- The id is no longer the primary key, the primary key of a partitioned table has to include the partition key.
+ALTER TABLE "geolocation" RENAME TO "geolocation_unpartitioned";
+ALTER SEQUENCE geolocation_id_seq OWNED BY NONE;
+
+CREATE TABLE "geolocation" (LIKE "geolocation_unpartitioned" INCLUDING DEFAULTS)
+    PARTITION BY LIST (family(ip_address));
+
+CREATE TABLE "geolocation_ipv4" PARTITION OF "geolocation" FOR VALUES IN (4);
+CREATE TABLE "geolocation_ipv6" PARTITION OF "geolocation" FOR VALUES IN (6);
+
+ALTER SEQUENCE geolocation_id_seq OWNED BY geolocation.id;
+
+-- The history of the geolocation data moved is kept as it is, the trigger recording it is created afterwards.
+INSERT INTO "geolocation" SELECT * FROM "geolocation_unpartitioned";
+
+DROP TABLE "geolocation_unpartitioned";
+
+-- Indexes of the partitioned table are created on each partition. The lookups always filter by dataset, the index on
+-- the IP address alone is not recreated.
+CREATE INDEX IF NOT EXISTS geolocation_dataset_ip_address_idx ON geolocation(dataset, ip_address);
+
+CREATE TRIGGER geolocation_history_record
+    AFTER INSERT OR UPDATE OR DELETE ON geolocation
+    FOR EACH ROW EXECUTE FUNCTION geolocation_history_record();
+
+-- geolocation_bulk_index keeps the definition of the indexes of geolocation dropped by a bulk load, to recreate them
+-- once the load ends, or by the next bulk load when it did not.
+CREATE TABLE IF NOT EXISTS "geolocation_bulk_index" (
+    name       TEXT PRIMARY KEY,
+    definition TEXT NOT NULL,
+
+    dropped_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
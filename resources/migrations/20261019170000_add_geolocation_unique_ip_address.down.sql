@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS geolocation_ipv4_dataset_ip_address_key;
+DROP INDEX IF EXISTS geolocation_ipv6_dataset_ip_address_key;
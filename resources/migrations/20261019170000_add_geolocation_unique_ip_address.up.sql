@@ -0,0 +1,12 @@
+-- A dataset keeps a single geolocation data per IP address, a batch saved again when retried is not stored twice.
+-- The geolocation data stored more than once so far is removed, the one not deleted and first stored being kept.
+DELETE FROM geolocation g
+USING geolocation o
+WHERE o.dataset = g.dataset
+    AND o.ip_address = g.ip_address
+    AND (o.deleted_at IS NOT NULL, o.id) < (g.deleted_at IS NOT NULL, g.id);
+
+-- A unique index of a table partitioned by an expression can not be defined on the table, it is defined on each
+-- partition. The indexes of the partitions are not dropped by bulk loads.
+CREATE UNIQUE INDEX IF NOT EXISTS geolocation_ipv4_dataset_ip_address_key ON geolocation_ipv4(dataset, ip_address);
+CREATE UNIQUE INDEX IF NOT EXISTS geolocation_ipv6_dataset_ip_address_key ON geolocation_ipv6(dataset, ip_address);
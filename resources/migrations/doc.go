@@ -0,0 +1,2 @@
+// Package migrations is a directory of database migrations.
+package migrations
@@ -0,0 +1,7 @@
+DROP TRIGGER IF EXISTS geolocation_history_delete;
+DROP TRIGGER IF EXISTS geolocation_history_update;
+DROP TRIGGER IF EXISTS geolocation_history_insert;
+
+DROP TABLE IF EXISTS geolocation_history;
+DROP TABLE IF EXISTS geolocation;
+DROP TABLE IF EXISTS dataset_version;
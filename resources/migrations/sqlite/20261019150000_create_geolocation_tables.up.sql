@@ -0,0 +1,122 @@
+-- The SQLite schema is the one of the Postgres migrations, up to 20261019140000_add_geolocation_provenance.
+--
+-- IP addresses are kept as text in their canonical form. Timestamps are kept as text in UTC, with milliseconds, so
+-- that they compare as text.
+CREATE TABLE IF NOT EXISTS dataset_version (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    dataset      TEXT NOT NULL DEFAULT 'default',
+    source       TEXT NOT NULL,
+    accepted     INTEGER NOT NULL DEFAULT 0,
+
+    created_at   TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    -- completed_at is NULL while the import is running, or when it failed.
+    completed_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS geolocation (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    ip_address      TEXT NOT NULL,
+    country_code    TEXT NOT NULL,
+    country         TEXT NOT NULL,
+    city            TEXT NOT NULL,
+    latitude        REAL NOT NULL,
+    longitude       REAL NOT NULL,
+    mystery_value   INTEGER,
+    region          TEXT NOT NULL DEFAULT '',
+    postal_code     TEXT NOT NULL DEFAULT '',
+    time_zone       TEXT NOT NULL DEFAULT '',
+    asn             INTEGER NOT NULL DEFAULT 0,
+    organization    TEXT NOT NULL DEFAULT '',
+    accuracy_radius INTEGER NOT NULL DEFAULT 0,
+    attributes      TEXT NOT NULL DEFAULT '{}',
+    -- version_id is the dataset version which last inserted or changed the geolocation, 0 when unknown.
+    version_id      INTEGER NOT NULL DEFAULT 0,
+    -- dataset is the name of the dataset the geolocation data belongs to, i.e. the provider.
+    dataset         TEXT NOT NULL DEFAULT 'default',
+    source_file     TEXT NOT NULL DEFAULT '',
+    source_line     INTEGER NOT NULL DEFAULT 0,
+
+    created_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    updated_at TIMESTAMP DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now')),
+    deleted_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS geolocation_dataset_ip_address_idx ON geolocation(dataset, ip_address);
+
+-- geolocation_history keeps every state of the geolocation data, valid from valid_from until valid_to, excluded.
+-- The current state has no valid_to.
+CREATE TABLE IF NOT EXISTS geolocation_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+
+    ip_address      TEXT NOT NULL,
+    country_code    TEXT NOT NULL,
+    country         TEXT NOT NULL,
+    city            TEXT NOT NULL,
+    latitude        REAL NOT NULL,
+    longitude       REAL NOT NULL,
+    mystery_value   INTEGER,
+    region          TEXT NOT NULL DEFAULT '',
+    postal_code     TEXT NOT NULL DEFAULT '',
+    time_zone       TEXT NOT NULL DEFAULT '',
+    asn             INTEGER NOT NULL DEFAULT 0,
+    organization    TEXT NOT NULL DEFAULT '',
+    accuracy_radius INTEGER NOT NULL DEFAULT 0,
+    attributes      TEXT NOT NULL DEFAULT '{}',
+    version_id      INTEGER NOT NULL DEFAULT 0,
+    dataset         TEXT NOT NULL DEFAULT 'default',
+    source_file     TEXT NOT NULL DEFAULT '',
+    source_line     INTEGER NOT NULL DEFAULT 0,
+
+    valid_from TIMESTAMP NOT NULL,
+    valid_to   TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS geolocation_history_ip_address_idx ON geolocation_history(ip_address, valid_from);
+
+-- The history is kept by the database, so that every write to the geolocation table is recorded.
+CREATE TRIGGER IF NOT EXISTS geolocation_history_insert
+    AFTER INSERT ON geolocation
+    FOR EACH ROW WHEN NEW.deleted_at IS NULL
+BEGIN
+    INSERT INTO geolocation_history (
+        ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code,
+        time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line,
+        valid_from
+    ) VALUES (
+        NEW.ip_address, NEW.country_code, NEW.country, NEW.city, NEW.latitude, NEW.longitude, NEW.mystery_value,
+        NEW.region, NEW.postal_code, NEW.time_zone, NEW.asn, NEW.organization, NEW.accuracy_radius,
+        NEW.attributes, NEW.version_id, NEW.dataset, NEW.source_file, NEW.source_line,
+        strftime('%Y-%m-%d %H:%M:%f', 'now')
+    );
+END;
+
+CREATE TRIGGER IF NOT EXISTS geolocation_history_update
+    AFTER UPDATE ON geolocation
+    FOR EACH ROW
+BEGIN
+    UPDATE geolocation_history
+    SET valid_to = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE ip_address = OLD.ip_address AND dataset = OLD.dataset AND valid_to IS NULL;
+
+    INSERT INTO geolocation_history (
+        ip_address, country_code, country, city, latitude, longitude, mystery_value, region, postal_code,
+        time_zone, asn, organization, accuracy_radius, attributes, version_id, dataset, source_file, source_line,
+        valid_from
+    )
+    SELECT NEW.ip_address, NEW.country_code, NEW.country, NEW.city, NEW.latitude, NEW.longitude, NEW.mystery_value,
+        NEW.region, NEW.postal_code, NEW.time_zone, NEW.asn, NEW.organization, NEW.accuracy_radius,
+        NEW.attributes, NEW.version_id, NEW.dataset, NEW.source_file, NEW.source_line,
+        strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE NEW.deleted_at IS NULL;
+END;
+
+CREATE TRIGGER IF NOT EXISTS geolocation_history_delete
+    AFTER DELETE ON geolocation
+    FOR EACH ROW
+BEGIN
+    UPDATE geolocation_history
+    SET valid_to = strftime('%Y-%m-%d %H:%M:%f', 'now')
+    WHERE ip_address = OLD.ip_address AND dataset = OLD.dataset AND valid_to IS NULL;
+END;
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS geolocation_bulk_index;
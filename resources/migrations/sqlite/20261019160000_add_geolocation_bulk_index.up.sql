@@ -0,0 +1,8 @@
+-- geolocation_bulk_index keeps the definition of the indexes of geolocation dropped by a bulk load, to recreate them
+-- once the load ends, or by the next bulk load when it did not. SQLite tables are not partitioned.
+CREATE TABLE IF NOT EXISTS geolocation_bulk_index (
+    name       TEXT PRIMARY KEY,
+    definition TEXT NOT NULL,
+
+    dropped_at TIMESTAMP NOT NULL DEFAULT (strftime('%Y-%m-%d %H:%M:%f', 'now'))
+);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS geolocation_dataset_ip_address_key;
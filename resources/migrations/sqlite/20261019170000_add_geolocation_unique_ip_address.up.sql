@@ -0,0 +1,12 @@
+-- A dataset keeps a single geolocation data per IP address, a batch saved again when retried is not stored twice.
+-- The geolocation data stored more than once so far is removed, the one not deleted and first stored being kept.
+DELETE FROM geolocation
+WHERE EXISTS (
+    SELECT 1 FROM geolocation o
+    WHERE o.dataset = geolocation.dataset
+        AND o.ip_address = geolocation.ip_address
+        AND (o.deleted_at IS NOT NULL, o.id) < (geolocation.deleted_at IS NOT NULL, geolocation.id)
+);
+
+-- The unique index is not dropped by bulk loads.
+CREATE UNIQUE INDEX IF NOT EXISTS geolocation_dataset_ip_address_key ON geolocation(dataset, ip_address);
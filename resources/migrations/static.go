@@ -0,0 +1,15 @@
+package migrations
+
+import (
+	"embed"
+)
+
+// Postgres contains the migrations of the Postgres database.
+//
+//go:embed *.sql
+var Postgres embed.FS
+
+// SQLite contains the migrations of the SQLite database, under the sqlite directory.
+//
+//go:embed sqlite/*.sql
+var SQLite embed.FS